@@ -0,0 +1,237 @@
+// Package codec provides the compact binary encoding behind the
+// MarshalBinary/UnmarshalBinary implementations of the persisted types.
+//
+// Values are written back to back without field tags: integers as varints,
+// strings and times length-prefixed, and decimals in udecimal's own binary
+// form, which preserves precision exactly. Each type's encoding starts with a
+// version byte so the layout can evolve.
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quagmt/udecimal"
+)
+
+// ErrMalformed indicates truncated or corrupt binary data.
+var ErrMalformed = errors.New("malformed binary data")
+
+// Encoder appends values to a buffer. The first error is sticky and
+// returned by Bytes.
+type Encoder struct {
+	buf []byte
+	err error
+}
+
+// NewEncoder returns an Encoder with capacity for size bytes.
+func NewEncoder(size int) *Encoder {
+	return &Encoder{buf: make([]byte, 0, size)}
+}
+
+// Bytes returns the encoded data, or the first error encountered.
+func (e *Encoder) Bytes() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf, nil
+}
+
+// Uint appends an unsigned varint.
+func (e *Encoder) Uint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// Int appends a signed varint.
+func (e *Encoder) Int(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+// Bool appends a single byte.
+func (e *Encoder) Bool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+		return
+	}
+	e.buf = append(e.buf, 0)
+}
+
+// String appends a length-prefixed string.
+func (e *Encoder) String(s string) {
+	e.Uint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// Time appends a length-prefixed time, keeping its zone offset.
+func (e *Encoder) Time(t time.Time) {
+	if e.err != nil {
+		return
+	}
+	b, err := t.MarshalBinary()
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.Uint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// Decimal appends d in udecimal's binary form, which is self-delimiting.
+func (e *Encoder) Decimal(d udecimal.Decimal) {
+	if e.err != nil {
+		return
+	}
+	buf, err := d.AppendBinary(e.buf)
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.buf = buf
+}
+
+// Version appends a layout version byte.
+func (e *Encoder) Version(v byte) {
+	e.buf = append(e.buf, v)
+}
+
+// Decoder reads values written by an Encoder. The first error is sticky:
+// later reads return zero values and Err reports it.
+type Decoder struct {
+	data []byte
+	err  error
+}
+
+// NewDecoder returns a Decoder over data.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Err returns the first error encountered, or ErrMalformed if unread data remains.
+func (d *Decoder) Err() error {
+	if d.err == nil && len(d.data) > 0 {
+		return ErrMalformed
+	}
+	return d.err
+}
+
+// Uint reads an unsigned varint.
+func (d *Decoder) Uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = ErrMalformed
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// Int reads a signed varint.
+func (d *Decoder) Int() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = ErrMalformed
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// Count reads a slice length, rejecting lengths the remaining data cannot
+// hold at minSize bytes per element.
+func (d *Decoder) Count(minSize int) int {
+	n := d.Uint()
+	if d.err == nil && n > uint64(len(d.data)/minSize) {
+		d.err = ErrMalformed
+		return 0
+	}
+	return int(n)
+}
+
+// Version reads a layout version byte and checks it is want.
+func (d *Decoder) Version(want byte) {
+	b := d.next(1)
+	if d.err == nil && b[0] != want {
+		d.err = fmt.Errorf("%w: unsupported version %d", ErrMalformed, b[0])
+	}
+}
+
+// VersionUpTo reads a layout version byte, checks it is between 1 and max
+// and returns it, for types that still decode their older layouts. It
+// returns 0 on error.
+func (d *Decoder) VersionUpTo(max byte) byte {
+	b := d.next(1)
+	if d.err != nil {
+		return 0
+	}
+	if b[0] < 1 || b[0] > max {
+		d.err = fmt.Errorf("%w: unsupported version %d", ErrMalformed, b[0])
+		return 0
+	}
+	return b[0]
+}
+
+// Bool reads a single byte.
+func (d *Decoder) Bool() bool {
+	b := d.next(1)
+	return len(b) == 1 && b[0] != 0
+}
+
+// String reads a length-prefixed string.
+func (d *Decoder) String() string {
+	return string(d.next(d.Uint()))
+}
+
+// Time reads a length-prefixed time.
+func (d *Decoder) Time() time.Time {
+	b := d.next(d.Uint())
+	if d.err != nil {
+		return time.Time{}
+	}
+	var t time.Time
+	if err := t.UnmarshalBinary(b); err != nil {
+		d.err = ErrMalformed
+	}
+	return t
+}
+
+// Decimal reads a decimal; its third byte holds the encoded length.
+func (d *Decoder) Decimal() udecimal.Decimal {
+	if d.err != nil {
+		return udecimal.Decimal{}
+	}
+	if len(d.data) < 3 {
+		d.err = ErrMalformed
+		return udecimal.Decimal{}
+	}
+	b := d.next(uint64(d.data[2]))
+	if d.err != nil {
+		return udecimal.Decimal{}
+	}
+	var v udecimal.Decimal
+	if err := v.UnmarshalBinary(b); err != nil {
+		d.err = ErrMalformed
+	}
+	return v
+}
+
+// next consumes n bytes.
+func (d *Decoder) next(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.err = ErrMalformed
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
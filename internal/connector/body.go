@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// ReadBody reads resp.Body, gunzipping it if the server sent
+// Content-Encoding: gzip, and fails with errors.ErrResponseTooLarge once more than
+// maxBytes have been decoded, so neither a huge body nor a decompression bomb
+// can exhaust memory. maxBytes <= 0 disables the limit. The body is closed.
+func ReadBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	defer resp.Body.Close()
+
+	var r io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip response: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return readLimited(r, maxBytes)
+}
+
+// readLimited reads r to the end, failing with errors.ErrResponseTooLarge
+// once more than maxBytes have been read (maxBytes <= 0 = unlimited).
+func readLimited(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return io.ReadAll(r)
+	}
+
+	// Read one byte past the limit to tell a body of exactly maxBytes from a longer one.
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", errors.ErrResponseTooLarge, maxBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,70 @@
+package connector
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DeflateExtension is the Sec-WebSocket-Extensions offer for
+// permessage-deflate (RFC 7692). Both directions are asked to compress every
+// message on its own, so messages can be inflated independently; a server
+// that cannot agree declines the extension and the connection proceeds
+// uncompressed.
+const DeflateExtension = "permessage-deflate; client_no_context_takeover; server_no_context_takeover"
+
+// deflateTail is the empty stored block stripped from the end of each
+// compressed message (RFC 7692 section 7.2.1).
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// RequestCompression adds the permessage-deflate offer to the headers of a
+// WebSocket handshake. Providers call it when stream.Config.EnableCompression
+// is set.
+func RequestCompression(h http.Header) {
+	h.Add("Sec-WebSocket-Extensions", DeflateExtension)
+}
+
+// CompressionAccepted reports whether the handshake response accepted
+// permessage-deflate. If not, frames arrive uncompressed and are used as is.
+func CompressionAccepted(resp *http.Response) bool {
+	for _, ext := range resp.Header.Values("Sec-WebSocket-Extensions") {
+		for offer := range strings.SplitSeq(ext, ",") {
+			name, _, _ := strings.Cut(offer, ";")
+			if strings.EqualFold(strings.TrimSpace(name), "permessage-deflate") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InflateMessage decompresses the payload of a WebSocket message whose first
+// frame had RSV1 set. Like ReadBody, it fails with errors.ErrResponseTooLarge
+// once more than maxBytes have been inflated (maxBytes <= 0 = unlimited).
+func InflateMessage(payload []byte, maxBytes int64) ([]byte, error) {
+	fr := flate.NewReader(io.MultiReader(bytes.NewReader(payload), bytes.NewReader(deflateTail)))
+	defer fr.Close()
+	data, err := readLimited(messageReader{fr}, maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("inflate message: %w", err)
+	}
+	return data, nil
+}
+
+// messageReader ends at the sync flush that closes a message, which flate
+// reports as io.ErrUnexpectedEOF since no final block follows.
+type messageReader struct {
+	io.Reader
+}
+
+// Read implements io.Reader.
+func (r messageReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/internal/infra"
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// DeadManSwitch keeps an exchange's cancel-on-disconnect timer armed for
+// Client.SetCancelOnDisconnect by renewing it every third of its window.
+// A failed renewal is logged and retried at the next tick; if renewals keep
+// failing the exchange cancels the orders, which is the point of the switch.
+type DeadManSwitch struct {
+	// send arms the exchange timer for window, or disarms it if window is 0.
+	send func(ctx context.Context, window time.Duration) error
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDeadManSwitch returns a switch that arms the exchange timer with send.
+func NewDeadManSwitch(send func(ctx context.Context, window time.Duration) error) *DeadManSwitch {
+	return &DeadManSwitch{send: send}
+}
+
+// Set arms the timer for window and starts renewing it, replacing any
+// previous renewal. A zero window disarms it.
+func (d *DeadManSwitch) Set(ctx context.Context, window time.Duration) error {
+	if window < 0 {
+		return errors.NewValidationError("window", "must be non-negative")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopLocked()
+
+	if err := d.send(ctx, window); err != nil {
+		return err
+	}
+	if window == 0 {
+		return nil
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.renew(renewCtx, window, d.done)
+	return nil
+}
+
+// Stop stops renewing without disarming the exchange timer, so the orders
+// are cancelled once the window elapses. Call it from Client.Close.
+func (d *DeadManSwitch) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopLocked()
+}
+
+// stopLocked stops the renewal goroutine and waits for it to exit.
+func (d *DeadManSwitch) stopLocked() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+	d.cancel, d.done = nil, nil
+}
+
+// renew re-arms the timer every window/3 until ctx is cancelled.
+func (d *DeadManSwitch) renew(ctx context.Context, window time.Duration, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(max(window/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.send(ctx, window); err != nil && ctx.Err() == nil {
+				infra.Warn().Err(err).Dur("window", window).Msg("cancel-on-disconnect renewal failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// ExchangeInfoCache serves exchange info from memory, fetching it on first use
+// and again once it is older than the TTL, so per-order precision lookups do
+// not cost rate limit. Concurrent callers share a single fetch. It is safe
+// for concurrent use.
+type ExchangeInfoCache struct {
+	ttl   time.Duration
+	clock stream.Clock
+	fetch func(ctx context.Context) ([]market.SymbolInfo, error)
+
+	mu        sync.Mutex
+	infos     []market.SymbolInfo
+	bySymbol  map[market.Symbol]int
+	fetchedAt time.Time
+}
+
+// NewExchangeInfoCache returns a cache that loads exchange info with fetch
+// and keeps it for ttl (Options.ExchangeInfoTTL; 0 fetches on every call).
+// clock measures the age; nil = stream.SystemClock.
+func NewExchangeInfoCache(ttl time.Duration, clock stream.Clock, fetch func(ctx context.Context) ([]market.SymbolInfo, error)) *ExchangeInfoCache {
+	if clock == nil {
+		clock = stream.SystemClock
+	}
+	return &ExchangeInfoCache{ttl: ttl, clock: clock, fetch: fetch}
+}
+
+// All returns every symbol's info, refreshing it first if it has expired.
+// The slice is a copy.
+func (c *ExchangeInfoCache) All(ctx context.Context) ([]market.SymbolInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensure(ctx); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.infos), nil
+}
+
+// Symbol returns the info of symbol, normalized with market.NewSymbol,
+// refreshing the cache first if it has expired. It returns an error wrapping
+// errors.ErrInvalidSymbol if the exchange does not list symbol.
+func (c *ExchangeInfoCache) Symbol(ctx context.Context, symbol market.Symbol) (*market.SymbolInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensure(ctx); err != nil {
+		return nil, err
+	}
+	i, ok := c.bySymbol[market.NewSymbol(string(symbol))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not listed", errors.ErrInvalidSymbol, symbol)
+	}
+	info := c.infos[i]
+	return &info, nil
+}
+
+// Has reports whether the exchange lists symbol, normalized with
+// market.NewSymbol, refreshing the cache first if it has expired.
+func (c *ExchangeInfoCache) Has(ctx context.Context, symbol market.Symbol) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensure(ctx); err != nil {
+		return false, err
+	}
+	_, ok := c.bySymbol[market.NewSymbol(string(symbol))]
+	return ok, nil
+}
+
+// Refresh fetches the exchange info now, whatever its age. On error the
+// previous data is kept.
+func (c *ExchangeInfoCache) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load(ctx)
+}
+
+// ensure loads the info if it was never fetched or has expired.
+// c.mu must be held.
+func (c *ExchangeInfoCache) ensure(ctx context.Context) error {
+	if c.infos != nil && c.clock.Now().Sub(c.fetchedAt) < c.ttl {
+		return nil
+	}
+	return c.load(ctx)
+}
+
+// load fetches and indexes the info. c.mu must be held.
+func (c *ExchangeInfoCache) load(ctx context.Context) error {
+	infos, err := c.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch exchange info: %w", err)
+	}
+	bySymbol := make(map[market.Symbol]int, len(infos))
+	for i, info := range infos {
+		bySymbol[market.NewSymbol(string(info.Symbol))] = i
+	}
+	c.infos = append([]market.SymbolInfo{}, infos...) // Non-nil marks it fetched
+	c.bySymbol = bySymbol
+	c.fetchedAt = c.clock.Now()
+	return nil
+}
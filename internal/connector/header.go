@@ -0,0 +1,15 @@
+package connector
+
+import "net/http"
+
+// SetHeaders sets the User-Agent and extra headers of exchange.Options on
+// req. Providers call it before adding their authentication headers, which
+// therefore take precedence. An empty userAgent leaves Go's default.
+func SetHeaders(req *http.Request, userAgent string, headers http.Header) {
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	for key, values := range headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
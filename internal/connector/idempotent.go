@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// RetryIdempotent is Retry for requests that must not execute twice, such as
+// placing an order with a client ID. Once an attempt fails ambiguously (it
+// timed out or the connection dropped, so the exchange may have executed it),
+// every later attempt first calls lookup and, if it finds the result, returns
+// it instead of resending.
+//
+// lookup cannot see a request the exchange is still processing, so a late
+// execution is only caught by the lookup of a later attempt.
+func RetryIdempotent[T any](ctx context.Context, p RetryPolicy, do func(ctx context.Context) (T, error), lookup func(ctx context.Context) (T, bool, error)) (T, error) {
+	var (
+		result    T
+		ambiguous bool
+	)
+	err := Retry(ctx, p, func(ctx context.Context) error {
+		if ambiguous {
+			found, ok, err := lookup(ctx)
+			if err != nil {
+				return err
+			}
+			if ok {
+				result = found
+				return nil
+			}
+		}
+		v, err := do(ctx)
+		if err != nil {
+			ambiguous = ambiguous || isAmbiguous(err)
+			return err
+		}
+		result = v
+		return nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// isAmbiguous reports whether a request failing with err may still have been
+// executed: any retryable failure except a rate-limit rejection.
+func isAmbiguous(err error) bool {
+	return errors.IsRetryable(err) && !stderrors.Is(err, errors.ErrRateLimited)
+}
@@ -0,0 +1,73 @@
+package connector
+
+import (
+	"context"
+	"time"
+)
+
+// PageOptions controls Paginate. The zero value fetches until the endpoint
+// runs dry, without pacing or de-duplication.
+type PageOptions[T any] struct {
+	PageSize int           // Maximum items per page; a shorter page is the last
+	Limit    int           // Maximum items returned; 0 = unlimited
+	Interval time.Duration // Pause between pages to stay within the rate limit
+
+	// Key identifies an item for de-duplication, for endpoints whose
+	// inclusive time bounds return the boundary item on two pages.
+	Key func(T) string
+}
+
+// Paginate collects the pages of a cursor-paginated endpoint, starting at
+// cursor. page returns the items at a cursor and the cursor of the next page.
+// Fetching stops when a page is empty or shorter than PageSize, the next
+// cursor is zero or does not advance, Limit is reached, or ctx ends, in which
+// case the context error is returned. Pages are fetched sequentially and the
+// items returned in page order.
+func Paginate[T any, C comparable](ctx context.Context, opts PageOptions[T], cursor C, page func(ctx context.Context, cursor C) ([]T, C, error)) ([]T, error) {
+	var (
+		zero C
+		all  []T
+		seen map[string]struct{}
+	)
+	if opts.Key != nil {
+		seen = make(map[string]struct{})
+	}
+
+	for first := true; ; first = false {
+		if !first && opts.Interval > 0 {
+			timer := time.NewTimer(opts.Interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		items, next, err := page(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if seen != nil {
+				key := opts.Key(item)
+				if _, dup := seen[key]; dup {
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+			all = append(all, item)
+			if opts.Limit > 0 && len(all) == opts.Limit {
+				return all, nil
+			}
+		}
+
+		if len(items) == 0 || (opts.PageSize > 0 && len(items) < opts.PageSize) || next == zero || next == cursor {
+			return all, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,105 @@
+package connector
+
+import (
+	"context"
+	stderrors "errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// pages serves items 1..total in pages of size, with the cursor being the
+// next item to return.
+func pages(total, size int) func(ctx context.Context, cursor int) ([]int, int, error) {
+	return func(ctx context.Context, cursor int) ([]int, int, error) {
+		var items []int
+		for i := cursor; i <= total && len(items) < size; i++ {
+			items = append(items, i)
+		}
+		return items, cursor + len(items), nil
+	}
+}
+
+func TestPaginateLimit(t *testing.T) {
+	calls := 0
+	page := pages(100, 10)
+	got, err := Paginate(context.Background(), PageOptions[int]{PageSize: 10, Limit: 25}, 1,
+		func(ctx context.Context, cursor int) ([]int, int, error) {
+			calls++
+			return page(ctx, cursor)
+		})
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if len(got) != 25 || got[0] != 1 || got[24] != 25 {
+		t.Fatalf("got %d items %v, want 1..25", len(got), got)
+	}
+	if calls != 3 {
+		t.Fatalf("fetched %d pages, want 3", calls)
+	}
+}
+
+func TestPaginateShortPage(t *testing.T) {
+	got, err := Paginate(context.Background(), PageOptions[int]{PageSize: 10}, 1, pages(23, 10))
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if len(got) != 23 {
+		t.Fatalf("got %d items, want 23", len(got))
+	}
+}
+
+// TestPaginateCursorRepeat checks that a cursor that does not advance ends
+// the loop, and that Key drops the boundary item returned on both pages.
+func TestPaginateCursorRepeat(t *testing.T) {
+	calls := 0
+	page := func(ctx context.Context, cursor int) ([]int, int, error) {
+		calls++
+		switch cursor {
+		case 1:
+			return []int{1, 2, 3}, 3, nil // Inclusive bound: 3 comes again
+		default:
+			return []int{3, 4}, 3, nil // Cursor stuck
+		}
+	}
+	opts := PageOptions[int]{Key: func(v int) string { return strconv.Itoa(v) }}
+	got, err := Paginate(context.Background(), opts, 1, page)
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("got %v, want [1 2 3 4]", got)
+	}
+	if calls != 2 {
+		t.Fatalf("fetched %d pages, want 2", calls)
+	}
+}
+
+func TestPaginateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	page := pages(100, 10)
+	got, err := Paginate(ctx, PageOptions[int]{PageSize: 10}, 1,
+		func(ctx context.Context, cursor int) ([]int, int, error) {
+			if cursor > 10 {
+				cancel()
+			}
+			return page(ctx, cursor)
+		})
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if got != nil {
+		t.Fatalf("got %v, want nil on cancellation", got)
+	}
+}
+
+func TestPaginatePageError(t *testing.T) {
+	boom := stderrors.New("boom")
+	_, err := Paginate(context.Background(), PageOptions[int]{}, 1,
+		func(ctx context.Context, cursor int) ([]int, int, error) {
+			return nil, 0, boom
+		})
+	if !stderrors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+}
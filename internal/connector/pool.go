@@ -0,0 +1,237 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// SubscriptionKey identifies an upstream subscription.
+type SubscriptionKey struct {
+	Channel string // Provider channel (e.g., "trade", "ticker")
+	Symbol  string
+}
+
+// String implements fmt.Stringer.
+func (k SubscriptionKey) String() string {
+	return k.Channel + ":" + k.Symbol
+}
+
+// Pool shares one upstream stream per SubscriptionKey between any number of
+// subscribers. The upstream is subscribed when the first subscriber arrives,
+// fanned out to every subscriber, and unsubscribed when the last one leaves.
+// A slow subscriber only drops its own values.
+//
+// Providers keep one Pool per payload type and return Pool.Stream from their
+// stream methods, so callers see ordinary streams.
+type Pool[T any] struct {
+	provider string
+	cfg      stream.Config
+	open     func(key SubscriptionKey) stream.Stream[T]
+
+	mu   sync.Mutex
+	subs map[SubscriptionKey]*shared[T]
+}
+
+// shared is an upstream subscription and its subscribers.
+type shared[T any] struct {
+	upstream    stream.Stream[T]
+	cancel      context.CancelFunc
+	subscribers atomic.Pointer[[]*pooledStream[T]] // Copy-on-write, guarded by Pool.mu for writes
+	last        atomic.Pointer[T]                  // Most recent value, replayed to subscribers with Config.ReplayLast
+}
+
+// NewPool returns a Pool that opens upstream streams with open and configures
+// subscriber streams with cfg. Subscriber errors are labeled with provider.
+func NewPool[T any](provider string, cfg stream.Config, open func(key SubscriptionKey) stream.Stream[T]) *Pool[T] {
+	return &Pool[T]{
+		provider: provider,
+		cfg:      cfg,
+		open:     open,
+		subs:     make(map[SubscriptionKey]*shared[T]),
+	}
+}
+
+// Stream returns a new subscriber stream for key. opts apply to the
+// subscriber only; the shared upstream keeps the pool's Config.
+func (p *Pool[T]) Stream(key SubscriptionKey, opts ...stream.Option) stream.Stream[T] {
+	s := &pooledStream[T]{BaseStream: stream.NewBaseStream[T](p.cfg.With(opts...)), pool: p, key: key}
+	s.SetLabels(stream.Labels{Provider: p.provider, Stream: key.Channel, Symbol: key.Symbol})
+	return s
+}
+
+// attach adds s to the subscribers of its key, subscribing the upstream if s
+// is the first.
+func (p *Pool[T]) attach(s *pooledStream[T]) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sh, ok := p.subs[s.key]
+	if !ok {
+		up := p.open(s.key)
+		ctx, cancel := context.WithCancel(context.Background())
+		data, err := up.Subscribe(ctx)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("subscribe %s: %w", s.key, err)
+		}
+		sh = &shared[T]{upstream: up, cancel: cancel}
+		sh.subscribers.Store(&[]*pooledStream[T]{})
+		p.subs[s.key] = sh
+		go p.fanOut(s.key, sh, data)
+		go p.fanOutErrors(sh)
+	}
+
+	subs := append(*sh.subscribers.Load(), s)
+	sh.subscribers.Store(&subs)
+	return nil
+}
+
+// detach removes s from the subscribers of its key, unsubscribing the
+// upstream if s was the last.
+func (p *Pool[T]) detach(s *pooledStream[T]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sh, ok := p.subs[s.key]
+	if !ok {
+		return
+	}
+	old := *sh.subscribers.Load()
+	subs := make([]*pooledStream[T], 0, len(old))
+	for _, sub := range old {
+		if sub != s {
+			subs = append(subs, sub)
+		}
+	}
+	sh.subscribers.Store(&subs)
+	if len(subs) == 0 {
+		delete(p.subs, s.key)
+		p.closeShared(sh)
+	}
+}
+
+// last returns the most recent value of the upstream for key, if shared.
+func (p *Pool[T]) last(key SubscriptionKey) (T, bool) {
+	p.mu.Lock()
+	sh, ok := p.subs[key]
+	p.mu.Unlock()
+	if ok {
+		if v := sh.last.Load(); v != nil {
+			return *v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// closeShared unsubscribes the upstream of sh.
+func (p *Pool[T]) closeShared(sh *shared[T]) {
+	sh.cancel()
+	_ = sh.upstream.Unsubscribe(context.Background())
+}
+
+// fanOut delivers upstream values to every subscriber. If the upstream ends
+// on its own, the subscribers are told and stopped.
+func (p *Pool[T]) fanOut(key SubscriptionKey, sh *shared[T], data <-chan T) {
+	for v := range data {
+		sh.last.Store(&v)
+		for _, s := range *sh.subscribers.Load() {
+			s.deliver(v)
+		}
+	}
+
+	p.mu.Lock()
+	if p.subs[key] != sh {
+		// Torn down by the last subscriber leaving.
+		p.mu.Unlock()
+		return
+	}
+	delete(p.subs, key)
+	subs := *sh.subscribers.Load()
+	sh.subscribers.Store(&[]*pooledStream[T]{})
+	p.mu.Unlock()
+
+	sh.cancel()
+	for _, s := range subs {
+		s.deliverError(fmt.Errorf("%w: upstream %s closed", errors.ErrDisconnected, key))
+		_ = s.Stop()
+	}
+}
+
+// fanOutErrors delivers upstream errors to every subscriber.
+func (p *Pool[T]) fanOutErrors(sh *shared[T]) {
+	for err := range sh.upstream.Errors() {
+		for _, s := range *sh.subscribers.Load() {
+			s.deliverError(err)
+		}
+	}
+}
+
+// pooledStream is a subscriber's view of a shared upstream subscription.
+type pooledStream[T any] struct {
+	*stream.BaseStream[T]
+	pool *Pool[T]
+	key  SubscriptionKey
+
+	// The fan-out may still hold the stream after it detached; detached stops
+	// deliveries before the stream's channels are closed.
+	mu       sync.RWMutex
+	detached bool
+}
+
+// Subscribe attaches the stream to the shared upstream subscription. With
+// Config.ReplayLast the upstream's last value is delivered first.
+func (s *pooledStream[T]) Subscribe(ctx context.Context) (<-chan T, error) {
+	if v, ok := s.pool.last(s.key); ok {
+		s.SetLast(v)
+	}
+	out := s.DataChannel()
+	err := s.Start(ctx, func(ctx context.Context) error {
+		if err := s.pool.attach(s); err != nil {
+			return err
+		}
+		defer s.detach()
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe detaches the stream; the upstream is closed with the last subscriber.
+func (s *pooledStream[T]) Unsubscribe(ctx context.Context) error {
+	return s.Stop()
+}
+
+// detach leaves the pool and blocks further deliveries.
+func (s *pooledStream[T]) detach() {
+	s.pool.detach(s)
+	s.mu.Lock()
+	s.detached = true
+	s.mu.Unlock()
+}
+
+// deliver emits v unless the stream has detached.
+func (s *pooledStream[T]) deliver(v T) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.detached {
+		s.Emit(v)
+	}
+}
+
+// deliverError emits err unless the stream has detached.
+func (s *pooledStream[T]) deliverError(err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.detached {
+		s.EmitError(err)
+	}
+}
@@ -0,0 +1,82 @@
+package connector
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/internal/infra"
+	"github.com/pwnholic/clara/pkg/exchange"
+)
+
+// RateLimitTracker keeps the rate-limit state parsed from REST response
+// headers for Client.RateLimitStatus, and logs a warning whenever usage
+// crosses Options.RateLimitWarnThreshold. It is safe for concurrent use.
+type RateLimitTracker struct {
+	warnAt float64
+
+	mu     sync.Mutex
+	status exchange.RateLimitStatus
+}
+
+// NewRateLimitTracker returns a tracker warning at warnAt (0 = never).
+func NewRateLimitTracker(warnAt float64) *RateLimitTracker {
+	return &RateLimitTracker{warnAt: warnAt}
+}
+
+// Status returns the latest recorded state.
+func (t *RateLimitTracker) Status() exchange.RateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// ObserveBinance records the X-MBX-USED-WEIGHT-1M header. Binance does not
+// send the limit, so it is passed in from exchangeInfo's REQUEST_WEIGHT rule.
+// The window is the current UTC minute.
+func (t *RateLimitTracker) ObserveBinance(h http.Header, limit int, now time.Time) {
+	used, err := strconv.Atoi(h.Get("X-MBX-USED-WEIGHT-1M"))
+	if err != nil {
+		return
+	}
+	t.record(exchange.RateLimitStatus{
+		UsedWeight: used,
+		Limit:      limit,
+		ResetAt:    now.UTC().Truncate(time.Minute).Add(time.Minute),
+	})
+}
+
+// ObserveBybit records the X-Bapi-Limit, X-Bapi-Limit-Status (remaining) and
+// X-Bapi-Limit-Reset-Timestamp (Unix milliseconds) headers.
+func (t *RateLimitTracker) ObserveBybit(h http.Header) {
+	limit, err := strconv.Atoi(h.Get("X-Bapi-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-Bapi-Limit-Status"))
+	if err != nil {
+		return
+	}
+	s := exchange.RateLimitStatus{UsedWeight: limit - remaining, Limit: limit}
+	if ms, err := strconv.ParseInt(h.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64); err == nil {
+		s.ResetAt = time.UnixMilli(ms)
+	}
+	t.record(s)
+}
+
+// record stores s and warns if it crosses the threshold.
+func (t *RateLimitTracker) record(s exchange.RateLimitStatus) {
+	t.mu.Lock()
+	prev := t.status
+	t.status = s
+	t.mu.Unlock()
+
+	if t.warnAt > 0 && prev.Usage() < t.warnAt && s.Usage() >= t.warnAt {
+		infra.Warn().
+			Int("used_weight", s.UsedWeight).
+			Int("limit", s.Limit).
+			Time("reset_at", s.ResetAt).
+			Msg("rate limit usage above threshold")
+	}
+}
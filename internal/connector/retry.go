@@ -0,0 +1,111 @@
+// Package connector provides the REST and WebSocket plumbing shared by
+// provider implementations.
+package connector
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// RetryPolicy controls Retry. Providers build it from exchange.Options:
+// RetryCount, RetryDelay, RetryMaxDelay, and Timeout as the attempt timeout.
+type RetryPolicy struct {
+	MaxRetries     int           // Retries after the first attempt
+	BaseDelay      time.Duration // Backoff before the first retry
+	MaxDelay       time.Duration // Backoff cap
+	AttemptTimeout time.Duration // Timeout of each attempt; 0 disables
+}
+
+// Retry calls fn until it succeeds, returns an error that errors.IsRetryable
+// rejects, or MaxRetries is exhausted. Each attempt gets its own context
+// bounded by AttemptTimeout, so a hung attempt is retried rather than
+// consuming the caller's whole deadline.
+//
+// Between attempts Retry waits an exponentially growing, jittered delay, or
+// longer if the error carries a Retry-After (see RetryAfter). It returns the
+// last error, or the context error if ctx ends first.
+func Retry(ctx context.Context, p RetryPolicy, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := attemptOnce(ctx, p.AttemptTimeout, fn)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= p.MaxRetries || !errors.IsRetryable(err) {
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if ra, ok := RetryAfter(err); ok && ra > delay {
+			delay = ra
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attemptOnce runs fn with an attempt-scoped timeout.
+func attemptOnce(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// backoff returns the delay before retry number attempt+1: the exponential
+// delay base*2^attempt capped at MaxDelay, with equal jitter (half fixed,
+// half random) so concurrent clients do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// RetryAfter returns the wait requested by the exchange in err's chain.
+func RetryAfter(err error) (time.Duration, bool) {
+	var exErr *errors.ExchangeError
+	if stderrors.As(err, &exErr) && exErr.RetryAfter > 0 {
+		return exErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// ParseRetryAfter parses a Retry-After header, given either in seconds or as
+// an HTTP date relative to now. Returns zero if absent or invalid.
+func ParseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/pwnholic/clara/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
@@ -151,6 +152,15 @@ func Fatal() *zerolog.Event {
 	return Logger.Fatal()
 }
 
+// WithErr adds err to e, along with the labels of the first
+// errors.StreamError in its chain so the error can be attributed to its stream.
+func WithErr(e *zerolog.Event, err error) *zerolog.Event {
+	if streamErr, ok := errors.AsStreamError(err); ok {
+		e = e.Fields(streamErr.Fields())
+	}
+	return e.Err(err)
+}
+
 // With returns a sub-logger with additional context.
 func With() zerolog.Context {
 	return Logger.With()
@@ -0,0 +1,86 @@
+package account
+
+import (
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/quagmt/udecimal"
+)
+
+// EstimateLiquidationPrice estimates the mark price at which pos is
+// liquidated, using the maintenance-margin formula of linear (USDT-margined)
+// perpetuals:
+//
+//	LP = (WB + cum - side*Q*EP) / (Q*MMR - side*Q)
+//
+// where side is 1 for longs and -1 for shorts, Q the absolute quantity, EP the
+// entry price, and MMR and cum the maintenance margin rate and amount of the
+// bracket holding the position's notional at LP.
+//
+// walletBalance (WB) is the collateral backing the position: the cross wallet
+// balance in cross mode, or the isolated margin in isolated mode, where
+// pos.Margin is used if walletBalance is zero.
+//
+// Assumptions: pos is the only position drawing on WB (in cross mode, other
+// positions' PnL and maintenance margin are ignored), fees and funding are
+// not accounted for, and quantities are in contracts of one base unit.
+// A zero price is returned when the collateral covers any adverse move.
+func EstimateLiquidationPrice(pos Position, brackets []LeverageBracket, walletBalance udecimal.Decimal) (udecimal.Decimal, error) {
+	if pos.IsClosed() {
+		return udecimal.Decimal{}, errors.NewValidationError("quantity", "position is closed")
+	}
+	if len(brackets) == 0 {
+		return udecimal.Decimal{}, errors.NewValidationError("brackets", "at least one leverage bracket is required")
+	}
+	if pos.MarginMode == MarginModeIsolated && walletBalance.IsZero() {
+		walletBalance = pos.Margin
+	}
+
+	// The bracket depends on the notional at the liquidation price, so take the
+	// first bracket whose estimate lands inside it.
+	var fallback udecimal.Decimal
+	for i, b := range brackets {
+		lp, err := liquidationPrice(pos, b, walletBalance)
+		if err != nil {
+			return udecimal.Decimal{}, err
+		}
+		if i == 0 {
+			fallback = lp
+		}
+		if b.Contains(lp.Mul(pos.AbsQty())) {
+			return lp, nil
+		}
+	}
+
+	// No consistent bracket; use the one holding the current notional.
+	price := pos.MarkPrice
+	if price.IsZero() {
+		price = pos.EntryPrice
+	}
+	b, err := BracketFor(brackets, price.Mul(pos.AbsQty()))
+	if err != nil {
+		return fallback, nil
+	}
+	return liquidationPrice(pos, b, walletBalance)
+}
+
+// liquidationPrice applies the liquidation formula for a single bracket.
+func liquidationPrice(pos Position, b LeverageBracket, wb udecimal.Decimal) (udecimal.Decimal, error) {
+	qty := pos.AbsQty()
+	signed := qty
+	if pos.signedQty().IsNeg() {
+		signed = qty.Neg()
+	}
+
+	num := wb.Add(b.MaintAmount).Sub(signed.Mul(pos.EntryPrice))
+	den := qty.Mul(b.MaintMarginRate).Sub(signed)
+	if den.IsZero() {
+		return udecimal.Decimal{}, errors.NewValidationError("maint_margin_rate", "short position with a maintenance margin rate of 1 cannot be liquidated")
+	}
+	lp, err := num.Div(den)
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	if !lp.IsPos() {
+		return udecimal.Decimal{}, nil
+	}
+	return lp, nil
+}
@@ -0,0 +1,70 @@
+package account
+
+import (
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// NetPosition combines all positions for symbol (e.g. the long and short legs
+// of hedge mode) into a single one-way position.
+//
+// The net Quantity is signed (negative when net short) and Side is set from its
+// sign, or PositionSideBoth when the legs cancel out. EntryPrice is the
+// quantity-weighted entry of the legs on the net side, since the opposing legs
+// are what was closed. PnL and margin are summed, MarkPrice and UpdateTime are
+// taken from the most recently updated leg, and LiquidationPrice is left zero
+// because it cannot be derived from the legs.
+func NetPosition(positions []Position, symbol market.Symbol) Position {
+	net := Position{Symbol: symbol, Side: PositionSideBoth}
+
+	var legs []Position
+	for _, p := range positions {
+		if p.Symbol != symbol {
+			continue
+		}
+		legs = append(legs, p)
+		net.Quantity = net.Quantity.Add(p.signedQty())
+		net.UnrealizedPnL = net.UnrealizedPnL.Add(p.UnrealizedPnL)
+		net.RealizedPnL = net.RealizedPnL.Add(p.RealizedPnL)
+		net.Margin = net.Margin.Add(p.Margin)
+		if net.UpdateTime.IsZero() || p.UpdateTime.After(net.UpdateTime) {
+			net.MarkPrice = p.MarkPrice
+			net.Leverage = p.Leverage
+			net.MarginMode = p.MarginMode
+			net.UpdateTime = p.UpdateTime
+		}
+	}
+
+	switch net.Quantity.Sign() {
+	case 1:
+		net.Side = PositionSideLong
+	case -1:
+		net.Side = PositionSideShort
+	default:
+		return net
+	}
+
+	var qty, cost udecimal.Decimal
+	for _, p := range legs {
+		if p.signedQty().Sign() != net.Quantity.Sign() {
+			continue
+		}
+		qty = qty.Add(p.AbsQty())
+		cost = cost.Add(p.EntryValue())
+	}
+	if !qty.IsZero() {
+		if entry, err := cost.Div(qty); err == nil {
+			net.EntryPrice = entry
+		}
+	}
+	return net
+}
+
+// signedQty returns the quantity signed by direction, treating a positive
+// quantity on a hedge-mode short leg as negative.
+func (p Position) signedQty() udecimal.Decimal {
+	if p.Side == PositionSideShort && p.Quantity.IsPos() {
+		return p.Quantity.Neg()
+	}
+	return p.Quantity
+}
@@ -0,0 +1,90 @@
+package account
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/order"
+)
+
+// Store holds account information shared between goroutines: one applies
+// user data updates while others read consistent point-in-time snapshots.
+// It is safe for concurrent use.
+type Store struct {
+	mu      sync.RWMutex
+	info    Info
+	updated map[string]time.Time // Last applied update time per asset
+
+	// snapshotTime is the UpdateTime of the info given to NewStore or Reset.
+	snapshotTime time.Time
+}
+
+// NewStore returns a Store initialized with a copy of info, typically from
+// exchange.Client.GetAccountInfo.
+func NewStore(info Info) *Store {
+	return &Store{info: info.Clone(), updated: make(map[string]time.Time), snapshotTime: info.UpdateTime}
+}
+
+// Apply applies a balance update, e.g. from exchange.Client.BalanceStream:
+// the balance of its asset is replaced, or added if the asset is new, and
+// UpdateTime advances to the update's timestamp. An update timestamped
+// before the last one applied to its asset (or, for an asset not updated
+// since the snapshot, before the snapshot's UpdateTime) arrived out of order
+// and is ignored, so it cannot overwrite newer state. Updates of other assets
+// do not affect it, and one without a timestamp is always applied.
+func (s *Store) Apply(u order.BalanceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !u.Timestamp.IsZero() {
+		last, ok := s.updated[u.Asset]
+		if !ok {
+			last = s.snapshotTime
+		}
+		if u.Timestamp.Before(last) {
+			return
+		}
+		s.updated[u.Asset] = u.Timestamp
+	}
+
+	if i := slices.IndexFunc(s.info.Balances, func(b order.Balance) bool { return b.Asset == u.Asset }); i >= 0 {
+		s.info.Balances[i] = u.Balance
+	} else {
+		s.info.Balances = append(s.info.Balances, u.Balance)
+	}
+	if u.Timestamp.After(s.info.UpdateTime) {
+		s.info.UpdateTime = u.Timestamp
+	}
+}
+
+// Reset replaces the stored information with a copy of info, e.g. after
+// refetching it when the user data stream reconnects.
+func (s *Store) Reset(info Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.info = info.Clone()
+	s.snapshotTime = info.UpdateTime
+	clear(s.updated)
+}
+
+// Snapshot returns a deep copy of the current account information.
+func (s *Store) Snapshot() Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.info.Clone()
+}
+
+// Balance returns the current balance of asset.
+func (s *Store) Balance(asset string) (order.Balance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, err := s.info.GetBalance(asset)
+	if err != nil {
+		return order.Balance{}, false
+	}
+	return *b, true
+}
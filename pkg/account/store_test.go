@@ -0,0 +1,36 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+// TestStoreApplyPerAsset checks that an update is only compared with earlier
+// updates of its own asset: a newer update of another asset must not make it
+// look stale, while an older update of the same asset is ignored.
+func TestStoreApplyPerAsset(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewStore(Info{UpdateTime: t0})
+	update := func(asset, free string, at time.Time) order.BalanceUpdate {
+		return order.BalanceUpdate{Balance: order.Balance{Asset: asset, Free: udecimal.MustParse(free)}, Timestamp: at}
+	}
+
+	s.Apply(update("USDT", "100", t0.Add(2*time.Second)))
+	s.Apply(update("BTC", "1", t0.Add(time.Second))) // Older than USDT's, still newest for BTC
+	s.Apply(update("USDT", "50", t0.Add(time.Second)))
+
+	if b, ok := s.Balance("BTC"); !ok || !b.Free.Equal(udecimal.MustParse("1")) {
+		t.Fatalf("BTC = %v, %t; want 1 applied", b.Free, ok)
+	}
+	if b, _ := s.Balance("USDT"); !b.Free.Equal(udecimal.MustParse("100")) {
+		t.Fatalf("USDT = %v, want 100 kept over the stale 50", b.Free)
+	}
+
+	s.Apply(update("ETH", "3", t0.Add(-time.Second)))
+	if _, ok := s.Balance("ETH"); ok {
+		t.Fatal("ETH update older than the snapshot applied")
+	}
+}
@@ -3,6 +3,8 @@ package account
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pwnholic/clara/pkg/errors"
@@ -38,6 +40,12 @@ func (a *Info) HasBalance(asset string) bool {
 	return !b.Total().IsZero()
 }
 
+// Clone returns a deep copy of the account information.
+func (a Info) Clone() Info {
+	a.Balances = slices.Clone(a.Balances)
+	return a
+}
+
 // PositionSide represents the position side for futures.
 type PositionSide int
 
@@ -61,6 +69,26 @@ func (p PositionSide) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (p PositionSide) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *PositionSide) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "BOTH", "NONE":
+		*p = PositionSideBoth
+	case "LONG":
+		*p = PositionSideLong
+	case "SHORT":
+		*p = PositionSideShort
+	default:
+		return errors.NewValidationError("position_side", fmt.Sprintf("unknown position side: %s", string(text)))
+	}
+	return nil
+}
+
 // MarginMode represents the margin mode for futures.
 type MarginMode int
 
@@ -81,6 +109,24 @@ func (m MarginMode) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (m MarginMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *MarginMode) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "CROSS", "CROSSED":
+		*m = MarginModeCross
+	case "ISOLATED":
+		*m = MarginModeIsolated
+	default:
+		return errors.NewValidationError("margin_mode", fmt.Sprintf("unknown margin mode: %s", string(text)))
+	}
+	return nil
+}
+
 // Position represents a futures position.
 type Position struct {
 	Symbol            market.Symbol    `json:"symbol"`
@@ -143,6 +189,46 @@ func (p Position) ROE() (udecimal.Decimal, error) {
 	return p.UnrealizedPnL.Div(p.Margin)
 }
 
+// ROEOr is like ROE but returns fallback if the margin is zero.
+func (p Position) ROEOr(fallback udecimal.Decimal) udecimal.Decimal {
+	return market.SafeDiv(p.UnrealizedPnL, p.Margin, fallback)
+}
+
+// UnrealizedPnLAt recomputes the unrealized PnL against mark:
+// (mark - EntryPrice) * quantity, with the quantity negative for shorts.
+func (p Position) UnrealizedPnLAt(mark udecimal.Decimal) udecimal.Decimal {
+	return mark.Sub(p.EntryPrice).Mul(p.signedQty())
+}
+
+// ROEAt returns the return on equity (0-1) of the PnL recomputed against mark.
+func (p Position) ROEAt(mark udecimal.Decimal) (udecimal.Decimal, error) {
+	if p.Margin.IsZero() {
+		return udecimal.Decimal{}, errors.NewValidationError("margin", "margin is zero")
+	}
+	return p.UnrealizedPnLAt(mark).Div(p.Margin)
+}
+
+// CloseRequest returns a reduce-only market order that closes the position:
+// a sell for a long and a buy for a short, sized to AbsQty. It returns nil if
+// the position is closed. In hedge mode the provider must also be told which
+// position side to reduce.
+func (p Position) CloseRequest() *order.Request {
+	if p.IsClosed() {
+		return nil
+	}
+	side := market.SideSell
+	if p.IsShort() {
+		side = market.SideBuy
+	}
+	return &order.Request{
+		Symbol:     p.Symbol,
+		Side:       side,
+		Type:       order.TypeMarket,
+		Quantity:   p.AbsQty(),
+		ReduceOnly: true,
+	}
+}
+
 // PnLPercent returns the PnL as a percentage of entry value.
 func (p Position) PnLPercent() (udecimal.Decimal, error) {
 	entryValue := p.EntryValue()
@@ -152,6 +238,12 @@ func (p Position) PnLPercent() (udecimal.Decimal, error) {
 	return p.UnrealizedPnL.Div(entryValue)
 }
 
+// PnLPercentOr is like PnLPercent but returns fallback if the entry value is
+// zero.
+func (p Position) PnLPercentOr(fallback udecimal.Decimal) udecimal.Decimal {
+	return market.SafeDiv(p.UnrealizedPnL, p.EntryValue(), fallback)
+}
+
 // LeverageSetting represents leverage settings for a symbol.
 type LeverageSetting struct {
 	Symbol      market.Symbol    `json:"symbol"`
@@ -163,3 +255,29 @@ type LeverageSetting struct {
 func (l LeverageSetting) IsMaxed() bool {
 	return l.Leverage.GreaterThanOrEqual(l.MaxLeverage)
 }
+
+// LeverageBracket is one notional tier of a symbol's leverage schedule.
+// Positions with notional in [NotionalFloor, NotionalCap) are limited to
+// MaxLeverage and maintained at MaintMarginRate.
+type LeverageBracket struct {
+	NotionalFloor   udecimal.Decimal `json:"notional_floor"`
+	NotionalCap     udecimal.Decimal `json:"notional_cap"`
+	MaxLeverage     udecimal.Decimal `json:"max_leverage"`
+	MaintMarginRate udecimal.Decimal `json:"maint_margin_rate"`
+	MaintAmount     udecimal.Decimal `json:"maint_amount"` // Cumulative maintenance deduction for the tier, zero if not published
+}
+
+// Contains returns true if notional falls within the bracket.
+func (b LeverageBracket) Contains(notional udecimal.Decimal) bool {
+	return notional.GreaterThanOrEqual(b.NotionalFloor) && notional.LessThan(b.NotionalCap)
+}
+
+// BracketFor returns the bracket containing notional.
+func BracketFor(brackets []LeverageBracket, notional udecimal.Decimal) (LeverageBracket, error) {
+	for _, b := range brackets {
+		if b.Contains(notional) {
+			return b, nil
+		}
+	}
+	return LeverageBracket{}, errors.NewValidationError("notional", fmt.Sprintf("no leverage bracket for notional %s", notional))
+}
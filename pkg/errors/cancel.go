@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IsOrderNotActive reports whether err indicates the order is no longer active:
+// it wraps ErrOrderNotActive, or it is an ExchangeError normalized to
+// CodeOrderNotFound (the order already filled, was cancelled or expired, or
+// is unknown to the matching engine). Such errors are benign when returned
+// from a cancel request, since the caller's intent already holds.
+func IsOrderNotActive(err error) bool {
+	return errors.Is(err, ErrOrderNotActive) || CodeOf(err) == CodeOrderNotFound
+}
+
+// NormalizeCancelError maps benign cancel failures to ErrOrderNotActive,
+// keeping the original error in the chain. Other errors are returned unchanged.
+// Provider implementations apply it to every CancelOrder error.
+func NormalizeCancelError(err error) error {
+	if err == nil || errors.Is(err, ErrOrderNotActive) {
+		return err
+	}
+	if IsOrderNotActive(err) {
+		return fmt.Errorf("%w: %w", ErrOrderNotActive, err)
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCode is a provider-independent classification of an exchange error,
+// for logic that must react the same way whatever venue rejected a request.
+type ErrorCode int
+
+const (
+	CodeUnknown              ErrorCode = iota // Not classified; inspect ExchangeError.Code
+	CodeInsufficientBalance                   // Not enough balance or margin
+	CodeOrderWouldTrigger                     // Stop price would trigger immediately
+	CodePriceFilter                           // Price outside the tick size or allowed range
+	CodeQuantityFilter                        // Quantity outside the step size or allowed range
+	CodeMinNotional                           // Order value below the minimum
+	CodeOrderNotFound                         // Order unknown, filled, or already cancelled
+	CodeDuplicateClientID                     // Client order ID already in use
+	CodePostOnlyRejected                      // Post-only order would have taken liquidity
+	CodeReduceOnlyRejected                    // Reduce-only order would increase the position
+	CodeInvalidSymbol                         // Symbol unknown or not trading
+	CodeRateLimited                           // Request or order rate limit exceeded
+	CodeUnauthorized                          // Invalid API key, signature, or permissions
+	CodeTimestampOutOfWindow                  // Request timestamp outside the receive window
+)
+
+var errorCodeNames = [...]string{
+	CodeUnknown:              "UNKNOWN",
+	CodeInsufficientBalance:  "INSUFFICIENT_BALANCE",
+	CodeOrderWouldTrigger:    "ORDER_WOULD_TRIGGER",
+	CodePriceFilter:          "PRICE_FILTER",
+	CodeQuantityFilter:       "QUANTITY_FILTER",
+	CodeMinNotional:          "MIN_NOTIONAL",
+	CodeOrderNotFound:        "ORDER_NOT_FOUND",
+	CodeDuplicateClientID:    "DUPLICATE_CLIENT_ID",
+	CodePostOnlyRejected:     "POST_ONLY_REJECTED",
+	CodeReduceOnlyRejected:   "REDUCE_ONLY_REJECTED",
+	CodeInvalidSymbol:        "INVALID_SYMBOL",
+	CodeRateLimited:          "RATE_LIMITED",
+	CodeUnauthorized:         "UNAUTHORIZED",
+	CodeTimestampOutOfWindow: "TIMESTAMP_OUT_OF_WINDOW",
+}
+
+// String implements fmt.Stringer.
+func (c ErrorCode) String() string {
+	if c >= 0 && int(c) < len(errorCodeNames) {
+		return errorCodeNames[c]
+	}
+	return "UNKNOWN"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c ErrorCode) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *ErrorCode) UnmarshalText(text []byte) error {
+	s := strings.ToUpper(string(text))
+	for i, name := range errorCodeNames {
+		if name == s {
+			*c = ErrorCode(i)
+			return nil
+		}
+	}
+	return NewValidationError("error_code", fmt.Sprintf("unknown error code: %s", string(text)))
+}
+
+// exchangeErrorCodes maps, per provider, raw exchange error codes to their
+// normalized ErrorCode. Codes missing here normalize to CodeUnknown.
+var exchangeErrorCodes = map[string]map[int]ErrorCode{
+	"binance": {
+		-1003: CodeRateLimited,          // TOO_MANY_REQUESTS
+		-1015: CodeRateLimited,          // TOO_MANY_ORDERS
+		-1021: CodeTimestampOutOfWindow, // INVALID_TIMESTAMP
+		-1022: CodeUnauthorized,         // INVALID_SIGNATURE
+		-1121: CodeInvalidSymbol,        // BAD_SYMBOL
+		-2011: CodeOrderNotFound,        // CANCEL_REJECTED "Unknown order sent."
+		-2013: CodeOrderNotFound,        // NO_SUCH_ORDER
+		-2014: CodeUnauthorized,         // BAD_API_KEY_FMT
+		-2015: CodeUnauthorized,         // REJECTED_MBX_KEY
+		-2018: CodeInsufficientBalance,  // BALANCE_NOT_SUFFICIENT
+		-2019: CodeInsufficientBalance,  // MARGIN_NOT_SUFFICIENT
+		-2021: CodeOrderWouldTrigger,    // ORDER_WOULD_IMMEDIATELY_TRIGGER
+		-2022: CodeReduceOnlyRejected,   // REDUCE_ONLY_REJECT
+		-4003: CodeQuantityFilter,       // QTY_LESS_THAN_ZERO
+		-4005: CodeQuantityFilter,       // QTY_GREATER_THAN_MAX_QTY
+		-4014: CodePriceFilter,          // PRICE_NOT_INCREASED_BY_TICK_SIZE
+		-4016: CodePriceFilter,          // PRICE_HIGHTER_THAN_MULTIPLIER_UP
+		-4023: CodeQuantityFilter,       // QTY_NOT_INCREASED_BY_STEP_SIZE
+		-4024: CodePriceFilter,          // PRICE_LOWER_THAN_MULTIPLIER_DOWN
+		-4116: CodeDuplicateClientID,    // DUPLICATED_CLIENT_ORDER_ID
+		-4164: CodeMinNotional,          // MIN_NOTIONAL
+		-5022: CodePostOnlyRejected,     // GTX_ORDER_REJECT
+	},
+	"bybit": {
+		10002:  CodeTimestampOutOfWindow, // Request time exceeds the time window
+		10003:  CodeUnauthorized,         // API key is invalid
+		10004:  CodeUnauthorized,         // Error sign
+		10005:  CodeUnauthorized,         // Permission denied
+		10006:  CodeRateLimited,          // Too many visits
+		10018:  CodeRateLimited,          // Exceeded the IP rate limit
+		110001: CodeOrderNotFound,        // Order does not exist
+		110003: CodePriceFilter,          // Order price exceeds the allowable range
+		110004: CodeInsufficientBalance,  // Wallet balance is insufficient
+		110007: CodeInsufficientBalance,  // Available balance is insufficient
+		110008: CodeOrderNotFound,        // The order has been finished or canceled
+		110012: CodeInsufficientBalance,  // Insufficient available balance
+		110017: CodeReduceOnlyRejected,   // Reduce-only rule not satisfied
+		110072: CodeDuplicateClientID,    // OrderLinkedID is duplicate
+		110092: CodeOrderWouldTrigger,    // Trigger price must be above the last price
+		110093: CodeOrderWouldTrigger,    // Trigger price must be below the last price
+		110094: CodeMinNotional,          // Order value below the minimum
+		170121: CodeInvalidSymbol,        // Invalid symbol (spot)
+		170131: CodeInsufficientBalance,  // Insufficient balance (spot)
+		170134: CodePriceFilter,          // Order price decimal too long (spot)
+		170136: CodeQuantityFilter,       // Order quantity decimal too long (spot)
+		170140: CodeMinNotional,          // Order value exceeded lower limit (spot)
+		170213: CodeOrderNotFound,        // Order does not exist (spot)
+	},
+}
+
+// binanceFilterFailures classifies Binance's generic rejection codes, -1013
+// (filter failure) and -2010 (new order rejected), by their message.
+var binanceFilterFailures = []struct {
+	substr string
+	code   ErrorCode
+}{
+	{"PRICE_FILTER", CodePriceFilter},
+	{"PERCENT_PRICE", CodePriceFilter},
+	{"LOT_SIZE", CodeQuantityFilter},
+	{"NOTIONAL", CodeMinNotional},
+	{"insufficient balance", CodeInsufficientBalance},
+	{"would trigger immediately", CodeOrderWouldTrigger},
+	{"would immediately match and take", CodePostOnlyRejected},
+}
+
+// NormalizeCode returns the ErrorCode for a provider's raw error code, using
+// message to disambiguate codes the exchange reuses for several rejections.
+func NormalizeCode(provider string, code int, message string) ErrorCode {
+	if c, ok := exchangeErrorCodes[provider][code]; ok {
+		return c
+	}
+	if provider == "binance" && (code == -1013 || code == -2010) {
+		for _, f := range binanceFilterFailures {
+			if strings.Contains(message, f.substr) {
+				return f.code
+			}
+		}
+	}
+	return CodeUnknown
+}
+
+// CodeOf returns the NormalizedCode of the first ExchangeError in err's chain,
+// or CodeUnknown if there is none.
+func CodeOf(err error) ErrorCode {
+	var exErr *ExchangeError
+	if errors.As(err, &exErr) {
+		return exErr.NormalizedCode
+	}
+	return CodeUnknown
+}
@@ -2,8 +2,12 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"time"
 )
 
 // Common errors that can be checked with errors.Is().
@@ -40,14 +44,39 @@ var (
 
 	// ErrOrderNotActive indicates the order is not active.
 	ErrOrderNotActive = errors.New("order not active")
+
+	// ErrNotSupported indicates the provider does not support the operation.
+	ErrNotSupported = errors.New("operation not supported")
+
+	// ErrQuoteExpired indicates a convert quote expired before it was accepted.
+	ErrQuoteExpired = errors.New("quote expired")
+
+	// ErrSequenceGap indicates an order book update does not follow the previous sequence.
+	ErrSequenceGap = errors.New("sequence gap")
+
+	// ErrChecksumMismatch indicates a local order book no longer matches the exchange checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrCrossedBook indicates an order book whose best bid is at or above its best ask.
+	ErrCrossedBook = errors.New("crossed order book")
+
+	// ErrStaleSnapshot indicates a saved order book too old to be brought up
+	// to date from the available diffs; a fresh snapshot is needed.
+	ErrStaleSnapshot = errors.New("stale snapshot")
+
+	// ErrResponseTooLarge indicates a response body, after decompression,
+	// longer than Options.MaxResponseBytes.
+	ErrResponseTooLarge = errors.New("response body too large")
 )
 
 // ExchangeError represents an error returned by an exchange API.
 type ExchangeError struct {
-	Provider string // Exchange provider name
-	Code     int    // Exchange-specific error code
-	Message  string // Error message from exchange
-	Err      error  // Underlying error
+	Provider       string        // Exchange provider name
+	Code           int           // Exchange-specific error code, kept for logging
+	NormalizedCode ErrorCode     // Provider-independent classification of Code
+	Message        string        // Error message from exchange
+	RetryAfter     time.Duration // Wait requested by the exchange (Retry-After), if any
+	Err            error         // Underlying error
 }
 
 func (e *ExchangeError) Error() string {
@@ -61,16 +90,42 @@ func (e *ExchangeError) Unwrap() error {
 	return e.Err
 }
 
-// NewExchangeError creates a new ExchangeError.
+// NewExchangeError creates a new ExchangeError, normalizing code through the
+// provider's error-mapping table.
 func NewExchangeError(provider string, code int, message string, err error) *ExchangeError {
 	return &ExchangeError{
-		Provider: provider,
-		Code:     code,
-		Message:  message,
-		Err:      err,
+		Provider:       provider,
+		Code:           code,
+		NormalizedCode: NormalizeCode(provider, code, message),
+		Message:        message,
+		Err:            err,
 	}
 }
 
+// IsRetryable reports whether the operation that produced err may succeed if
+// retried: rate limiting, timeouts, disconnects and network failures.
+// Authentication, validation, and order rejections are not retryable, nor is
+// context cancellation.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCancelled) {
+		return false
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+	switch {
+	case errors.Is(err, ErrRateLimited),
+		errors.Is(err, ErrTimeout),
+		errors.Is(err, ErrDisconnected),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 // ValidationError represents a validation error.
 type ValidationError struct {
 	Field   string // Field that failed validation
@@ -93,21 +148,46 @@ func NewValidationError(field, message string) *ValidationError {
 type StreamError struct {
 	Provider string // Exchange provider name
 	Stream   string // Stream name (e.g., "ticker", "orderbook")
+	Symbol   string // Trading symbol, empty for account-wide streams
 	Message  string // Error message
 	Err      error  // Underlying error
 }
 
 func (e *StreamError) Error() string {
-	if e.Err != nil {
-		return fmt.Sprintf("[%s] stream %s: %s: %v", e.Provider, e.Stream, e.Message, e.Err)
+	name := e.Stream
+	if e.Symbol != "" {
+		name += " " + e.Symbol
+	}
+	switch {
+	case e.Message == "":
+		return fmt.Sprintf("[%s] stream %s: %v", e.Provider, name, e.Err)
+	case e.Err != nil:
+		return fmt.Sprintf("[%s] stream %s: %s: %v", e.Provider, name, e.Message, e.Err)
+	default:
+		return fmt.Sprintf("[%s] stream %s: %s", e.Provider, name, e.Message)
 	}
-	return fmt.Sprintf("[%s] stream %s: %s", e.Provider, e.Stream, e.Message)
 }
 
 func (e *StreamError) Unwrap() error {
 	return e.Err
 }
 
+// Fields returns the labels identifying the stream, keyed "provider",
+// "stream" and "symbol", for structured logging. Empty labels are omitted.
+func (e *StreamError) Fields() map[string]any {
+	fields := make(map[string]any, 3)
+	if e.Provider != "" {
+		fields["provider"] = e.Provider
+	}
+	if e.Stream != "" {
+		fields["stream"] = e.Stream
+	}
+	if e.Symbol != "" {
+		fields["symbol"] = e.Symbol
+	}
+	return fields
+}
+
 // NewStreamError creates a new StreamError.
 func NewStreamError(provider, stream, message string, err error) *StreamError {
 	return &StreamError{
@@ -117,3 +197,12 @@ func NewStreamError(provider, stream, message string, err error) *StreamError {
 		Err:      err,
 	}
 }
+
+// AsStreamError returns the first StreamError in err's chain, if any.
+func AsStreamError(err error) (*StreamError, bool) {
+	var streamErr *StreamError
+	if errors.As(err, &streamErr) {
+		return streamErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,223 @@
+// Package aggregate combines streams from several exchanges into consolidated views.
+package aggregate
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/exchange"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// DefaultStaleAfter is how long a venue may go without a ticker update before
+// it is excluded from the consolidated ticker.
+const DefaultStaleAfter = 10 * time.Second
+
+// Option configures a consolidated ticker.
+type Option func(*consolidatedTicker)
+
+// WithStaleAfter sets how long a venue may go without an update before it is
+// excluded (default DefaultStaleAfter).
+func WithStaleAfter(d time.Duration) Option {
+	return func(c *consolidatedTicker) {
+		c.staleAfter = d
+	}
+}
+
+// venueTicker is a ticker update tagged with its venue.
+type venueTicker struct {
+	venue  exchange.Provider
+	ticker market.Ticker
+}
+
+// venueState is the latest ticker of a venue and when it was received.
+type venueState struct {
+	ticker market.Ticker
+	recvAt time.Time
+}
+
+type consolidatedTicker struct {
+	*stream.BaseStream[market.ConsolidatedTicker]
+	streams    map[exchange.Provider]stream.Stream[market.Ticker]
+	staleAfter time.Duration
+}
+
+// NewConsolidatedTicker returns a stream of the best bid and offer across the
+// ticker streams of several venues, which should all carry the same symbol.
+// A value is emitted after every update and whenever a venue is excluded for
+// going stale; venues whose ticker has no bid or ask do not contribute to
+// that side. Nothing is emitted while no venue is fresh. Errors of the
+// source streams are forwarded.
+func NewConsolidatedTicker(streams map[exchange.Provider]stream.Stream[market.Ticker], opts ...Option) stream.Stream[market.ConsolidatedTicker] {
+	c := &consolidatedTicker{
+		BaseStream: stream.NewBaseStream[market.ConsolidatedTicker](stream.DefaultConfig()),
+		streams:    streams,
+		staleAfter: DefaultStaleAfter,
+	}
+	c.SetLabels(stream.Labels{Stream: "consolidated_ticker"})
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Subscribe subscribes every venue stream and starts consolidating.
+func (c *consolidatedTicker) Subscribe(ctx context.Context) (<-chan market.ConsolidatedTicker, error) {
+	sources := make(map[exchange.Provider]<-chan market.Ticker, len(c.streams))
+	for venue, s := range c.streams {
+		ch, err := s.Subscribe(ctx)
+		if err != nil {
+			c.unsubscribeAll(ctx)
+			return nil, err
+		}
+		sources[venue] = ch
+	}
+	out := c.DataChannel()
+	if err := c.Start(ctx, func(ctx context.Context) error { return c.run(ctx, sources) }); err != nil {
+		c.unsubscribeAll(ctx)
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe stops the stream and unsubscribes every venue stream.
+func (c *consolidatedTicker) Unsubscribe(ctx context.Context) error {
+	if err := c.Stop(); err != nil {
+		return err
+	}
+	c.unsubscribeAll(ctx)
+	return nil
+}
+
+func (c *consolidatedTicker) unsubscribeAll(ctx context.Context) {
+	for _, s := range c.streams {
+		_ = s.Unsubscribe(ctx)
+	}
+}
+
+// run merges the venue streams until ctx is cancelled. A venue whose stream
+// closes is dropped; the stream ends when all have.
+func (c *consolidatedTicker) run(ctx context.Context, sources map[exchange.Provider]<-chan market.Ticker) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan venueTicker)
+	var wg sync.WaitGroup
+	for venue, ch := range sources {
+		wg.Go(func() { c.forward(ctx, venue, ch, updates) })
+		wg.Go(func() { c.forwardErrors(ctx, c.streams[venue]) })
+	}
+	sourcesDone := make(chan struct{})
+	go func() {
+		// Error channels close with their stream, so this fires once every
+		// venue stream has ended.
+		wg.Wait()
+		close(sourcesDone)
+	}()
+	defer func() {
+		cancel()
+		<-sourcesDone
+	}()
+
+	venues := make(map[exchange.Provider]venueState, len(sources))
+	ticker := time.NewTicker(max(c.staleAfter/2, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sourcesDone:
+			return nil
+		case u := <-updates:
+			venues[u.venue] = venueState{ticker: u.ticker, recvAt: c.Now()}
+			if ct, ok := c.consolidate(venues); ok {
+				c.Emit(ct)
+			}
+		case <-ticker.C:
+			if c.evictStale(venues) {
+				if ct, ok := c.consolidate(venues); ok {
+					c.Emit(ct)
+				}
+			}
+		}
+	}
+}
+
+// forward tags the tickers of one venue and sends them to updates.
+func (c *consolidatedTicker) forward(ctx context.Context, venue exchange.Provider, in <-chan market.Ticker, updates chan<- venueTicker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t, ok := <-in:
+			if !ok {
+				return
+			}
+			select {
+			case updates <- venueTicker{venue: venue, ticker: t}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// forwardErrors relays the errors of one venue stream.
+func (c *consolidatedTicker) forwardErrors(ctx context.Context, s stream.Stream[market.Ticker]) {
+	errs := s.Errors()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			c.EmitError(err)
+		}
+	}
+}
+
+// evictStale removes the venues not updated within staleAfter and reports
+// whether any was removed.
+func (c *consolidatedTicker) evictStale(venues map[exchange.Provider]venueState) bool {
+	now := c.Now()
+	evicted := false
+	for venue, v := range venues {
+		if now.Sub(v.recvAt) > c.staleAfter {
+			delete(venues, venue)
+			evicted = true
+		}
+	}
+	return evicted
+}
+
+// consolidate picks the best bid and ask among venues. Ties go to the venue
+// whose name sorts first.
+func (c *consolidatedTicker) consolidate(venues map[exchange.Provider]venueState) (market.ConsolidatedTicker, bool) {
+	if len(venues) == 0 {
+		return market.ConsolidatedTicker{}, false
+	}
+	var ct market.ConsolidatedTicker
+	for _, venue := range slices.Sorted(maps.Keys(venues)) {
+		t := venues[venue].ticker
+		ct.Venues = append(ct.Venues, venue.String())
+		if ct.Symbol == "" {
+			ct.Symbol = t.Symbol
+		}
+		if t.Timestamp.After(ct.Timestamp) {
+			ct.Timestamp = t.Timestamp
+		}
+		if t.BidPrice.IsPos() && (ct.BidVenue == "" || t.BidPrice.GreaterThan(ct.BidPrice)) {
+			ct.BidPrice, ct.BidQty, ct.BidVenue = t.BidPrice, t.BidQty, venue.String()
+		}
+		if t.AskPrice.IsPos() && (ct.AskVenue == "" || t.AskPrice.LessThan(ct.AskPrice)) {
+			ct.AskPrice, ct.AskQty, ct.AskVenue = t.AskPrice, t.AskQty, venue.String()
+		}
+	}
+	return ct, true
+}
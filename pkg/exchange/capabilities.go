@@ -0,0 +1,26 @@
+package exchange
+
+import (
+	"slices"
+
+	"github.com/pwnholic/clara/pkg/order"
+)
+
+// Capabilities describes the optional features a provider supports, so
+// provider-agnostic code can gate features up front instead of discovering
+// errors.ErrNotSupported at runtime.
+type Capabilities struct {
+	SupportsOCO           bool         // One-cancels-the-other order lists
+	SupportsWSOrders      bool         // Order placement over WebSocket
+	SupportsHedgeMode     bool         // Separate long and short positions per symbol
+	SupportsConvert       bool         // GetConvertQuote and AcceptConvertQuote
+	SupportsCancelReplace bool         // Atomic CancelReplace without the CancelThenPlace fallback
+	SupportsDeadMan       bool         // SetCancelOnDisconnect
+	SupportsListenKey     bool         // CreateListenKey, KeepAliveListenKey and CloseListenKey
+	SupportedOrderTypes   []order.Type // Order types accepted by PlaceOrder
+}
+
+// SupportsOrderType returns true if PlaceOrder accepts orders of type t.
+func (c Capabilities) SupportsOrderType(t order.Type) bool {
+	return slices.Contains(c.SupportedOrderTypes, t)
+}
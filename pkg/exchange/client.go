@@ -5,13 +5,17 @@ package exchange
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
+	"slices"
 	"time"
 
+	"github.com/pwnholic/clara/pkg/account"
 	"github.com/pwnholic/clara/pkg/errors"
 	"github.com/pwnholic/clara/pkg/market"
 	"github.com/pwnholic/clara/pkg/order"
 	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
 )
 
 // Provider identifies a specific exchange provider.
@@ -37,6 +41,25 @@ func (p Provider) IsValid() bool {
 	}
 }
 
+// MarketType identifies the product an order is placed on. Providers accept
+// different order types and parameters on each.
+type MarketType string
+
+const (
+	MarketSpot    MarketType = "spot"
+	MarketFutures MarketType = "futures" // Linear (USDⓈ-margined) perpetuals and futures
+)
+
+// String implements fmt.Stringer.
+func (m MarketType) String() string {
+	return string(m)
+}
+
+// IsValid returns true if the market type is valid.
+func (m MarketType) IsValid() bool {
+	return m == MarketSpot || m == MarketFutures
+}
+
 // Option is a functional option for configuring the client.
 type Option func(*Options)
 
@@ -51,26 +74,76 @@ type Options struct {
 	Testnet bool
 
 	// HTTP settings
-	HTTPClient *http.Client
-	Timeout    time.Duration
-	RetryCount int
-	RetryDelay time.Duration
+	HTTPClient       *http.Client
+	Timeout          time.Duration // Per-attempt request timeout
+	RetryCount       int           // Retries after the first attempt
+	RetryDelay       time.Duration // Base delay of the exponential backoff
+	RetryMaxDelay    time.Duration // Maximum backoff delay
+	RecvWindow       time.Duration // Clock skew tolerated on signed requests; 0 uses the provider default
+	MaxResponseBytes int64         // Decoded response body limit; 0 = unlimited
+	UserAgent        string        // User-Agent of every request; defaults to DefaultUserAgent
+	Headers          http.Header   // Extra headers added to every request
+
+	// IdempotentOrders makes PlaceOrder treat ClientID as an idempotency key
+	// (see WithIdempotentOrders).
+	IdempotentOrders bool
+
+	// RateLimitWarnThreshold is the fraction of the rate limit (0-1) above
+	// which a warning is logged; 0 disables it.
+	RateLimitWarnThreshold float64
+
+	// ExchangeInfoTTL is how long exchange info (symbols and their trading
+	// rules) is cached before it is fetched again; 0 disables the cache.
+	ExchangeInfoTTL time.Duration
+
+	// Order guardrails (see WithSymbolAllowlist and WithSymbolDenylist)
+	SymbolAllowlist []market.Symbol // nil = every symbol allowed
+	SymbolDenylist  []market.Symbol
+
+	// Notional caps by quote asset (see WithMaxOrderNotional and WithMaxPositionNotional)
+	MaxOrderNotional    map[string]udecimal.Decimal
+	MaxPositionNotional map[string]udecimal.Decimal
 
 	// Stream settings
 	StreamConfig stream.Config
 
+	// Clock stamps timestamps and measures skew and staleness; it also becomes
+	// StreamConfig.Clock unless that is set. nil = stream.SystemClock.
+	Clock stream.Clock
+
 	// Debug
-	Debug bool
-	Logger interface{ Debug(msg string, fields ...interface{}) }
+	Debug       bool
+	Logger      interface{ Debug(msg string, fields ...interface{}) }
+	FrameLogger func(direction string, data []byte) // Raw WebSocket frames; nil = disabled
 }
 
+// Frame directions passed to Options.FrameLogger.
+const (
+	FrameInbound  = "inbound"
+	FrameOutbound = "outbound"
+)
+
+// MaxRecvWindow is the largest recvWindow exchanges accept (Binance: 60s).
+const MaxRecvWindow = 60 * time.Second
+
+// DefaultMaxResponseBytes bounds response bodies by default, well above the
+// largest legitimate response (full exchange info).
+const DefaultMaxResponseBytes = 64 << 20
+
+// DefaultExchangeInfoTTL is how long exchange info is cached by default.
+const DefaultExchangeInfoTTL = time.Hour
+
 // DefaultOptions returns Options with sensible defaults.
 func DefaultOptions() Options {
 	return Options{
-		Timeout:      30 * time.Second,
-		RetryCount:   3,
-		RetryDelay:   time.Second,
-		StreamConfig: stream.DefaultConfig(),
+		Timeout:          30 * time.Second,
+		RetryCount:       3,
+		RetryDelay:       time.Second,
+		RetryMaxDelay:    30 * time.Second,
+		MaxResponseBytes: DefaultMaxResponseBytes,
+		UserAgent:        DefaultUserAgent,
+		ExchangeInfoTTL:  DefaultExchangeInfoTTL,
+		StreamConfig:     stream.DefaultConfig(),
 	}
 }
 
@@ -116,11 +189,128 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// WithRetry sets the retry configuration.
+// WithRetry sets the retry configuration. RetryMaxDelay is raised to delay
+// if it is lower, so the backoff never caps below the first delay.
 func WithRetry(count int, delay time.Duration) Option {
 	return func(o *Options) {
 		o.RetryCount = count
 		o.RetryDelay = delay
+		o.RetryMaxDelay = max(o.RetryMaxDelay, delay)
+	}
+}
+
+// WithBackoff sets the exponential backoff between retries. Each delay is
+// jittered, capped at max, and extended to any Retry-After the exchange sends.
+func WithBackoff(base, max time.Duration) Option {
+	return func(o *Options) {
+		o.RetryDelay = base
+		o.RetryMaxDelay = max
+	}
+}
+
+// WithRecvWindow sets how long after its timestamp a signed request is still
+// accepted, clamped to MaxRecvWindow. Providers send it with every signed
+// request (Binance recvWindow, Bybit X-BAPI-RECV-WINDOW) in milliseconds.
+// Raise it when requests are rejected for clock skew on loaded machines.
+func WithRecvWindow(d time.Duration) Option {
+	return func(o *Options) {
+		o.RecvWindow = min(d, MaxRecvWindow)
+	}
+}
+
+// WithMaxResponseBytes caps the size of a REST response body, measured after
+// gzip decompression; reading past it fails with errors.ErrResponseTooLarge
+// instead of exhausting memory. n <= 0 removes the limit.
+func WithMaxResponseBytes(n int64) Option {
+	return func(o *Options) {
+		o.MaxResponseBytes = max(n, 0)
+	}
+}
+
+// WithUserAgent sets the User-Agent of every HTTP request, replacing
+// DefaultUserAgent.
+func WithUserAgent(ua string) Option {
+	return func(o *Options) {
+		o.UserAgent = ua
+	}
+}
+
+// WithHeader adds a header to every HTTP request, e.g. a tag for the
+// exchange's support team. It replaces earlier values of key but cannot
+// override the authentication headers a provider sets.
+func WithHeader(key, value string) Option {
+	return func(o *Options) {
+		if o.Headers == nil {
+			o.Headers = make(http.Header)
+		}
+		o.Headers.Set(key, value)
+	}
+}
+
+// WithExchangeInfoTTL sets how long exchange info is cached before
+// GetSymbolInfo and GetExchangeInfo fetch it again (see
+// DefaultExchangeInfoTTL). 0 fetches it on every call.
+func WithExchangeInfoTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.ExchangeInfoTTL = d
+	}
+}
+
+// WithIdempotentOrders makes PlaceOrder safe to retry: after an attempt times
+// out or loses its connection, the order is looked up by ClientID before it is
+// resent, and an order the exchange already accepted is returned instead of
+// placing a duplicate. Requests without a ClientID get one from
+// order.NewClientIDAt, stamped with Options.Clock.
+func WithIdempotentOrders() Option {
+	return func(o *Options) {
+		o.IdempotentOrders = true
+	}
+}
+
+// WithRateLimitWarning logs a warning when the request weight used in the
+// current window crosses threshold, a fraction of the limit (e.g., 0.8).
+func WithRateLimitWarning(threshold float64) Option {
+	return func(o *Options) {
+		o.RateLimitWarnThreshold = threshold
+	}
+}
+
+// WithSymbolAllowlist restricts PlaceOrder and CancelReplace to symbols:
+// orders for any other symbol are rejected with a ValidationError before they
+// are sent. A nil list allows every symbol; an empty one blocks every order.
+func WithSymbolAllowlist(symbols []market.Symbol) Option {
+	return func(o *Options) {
+		o.SymbolAllowlist = slices.Clone(symbols)
+	}
+}
+
+// WithSymbolDenylist makes PlaceOrder and CancelReplace reject orders for
+// symbols with a ValidationError before they are sent. It takes precedence
+// over WithSymbolAllowlist.
+func WithSymbolDenylist(symbols []market.Symbol) Option {
+	return func(o *Options) {
+		o.SymbolDenylist = slices.Clone(symbols)
+	}
+}
+
+// WithMaxOrderNotional makes PlaceOrder and CancelReplace reject, with a
+// ValidationError, orders in symbols quoted in quote that are worth more than
+// max. Limit and stop orders are valued at their price and market orders at
+// the current touch, fetched with GetTicker. Call it once per quote asset.
+func WithMaxOrderNotional(quote string, max udecimal.Decimal) Option {
+	return func(o *Options) {
+		setNotionalCap(&o.MaxOrderNotional, quote, max)
+	}
+}
+
+// WithMaxPositionNotional makes PlaceOrder and CancelReplace reject, with a
+// ValidationError, orders that would grow the position in a symbol quoted in
+// quote beyond max, valued at the order price. The position is fetched with
+// GetPositions before every such order; providers without positions count as
+// flat. Orders that reduce the position are always allowed.
+func WithMaxPositionNotional(quote string, max udecimal.Decimal) Option {
+	return func(o *Options) {
+		setNotionalCap(&o.MaxPositionNotional, quote, max)
 	}
 }
 
@@ -131,6 +321,13 @@ func WithStreamConfig(cfg stream.Config) Option {
 	}
 }
 
+// WithClock sets the time source, e.g. a simulated clock for tests and replay.
+func WithClock(c stream.Clock) Option {
+	return func(o *Options) {
+		o.Clock = c
+	}
+}
+
 // WithDebug enables debug mode.
 func WithDebug() Option {
 	return func(o *Options) {
@@ -138,6 +335,16 @@ func WithDebug() Option {
 	}
 }
 
+// WithFrameLogger sets a hook that receives every raw WebSocket message, with
+// direction FrameInbound or FrameOutbound, before it is parsed or after it is
+// encoded. fn runs on the connection's goroutine and must neither block nor
+// retain or modify data. When unset, frames are not inspected at all.
+func WithFrameLogger(fn func(direction string, data []byte)) Option {
+	return func(o *Options) {
+		o.FrameLogger = fn
+	}
+}
+
 // Validate validates the options.
 func (o Options) Validate() error {
 	if o.Timeout <= 0 {
@@ -146,6 +353,34 @@ func (o Options) Validate() error {
 	if o.RetryCount < 0 {
 		return errors.NewValidationError("retry_count", "must be non-negative")
 	}
+	if o.RetryDelay < 0 {
+		return errors.NewValidationError("retry_delay", "must be non-negative")
+	}
+	if o.RetryMaxDelay < o.RetryDelay {
+		return errors.NewValidationError("retry_max_delay", "must be >= retry_delay")
+	}
+	if o.RecvWindow < 0 || o.RecvWindow > MaxRecvWindow {
+		return errors.NewValidationError("recv_window", fmt.Sprintf("must be between 0 and %s", MaxRecvWindow))
+	}
+	if o.MaxResponseBytes < 0 {
+		return errors.NewValidationError("max_response_bytes", "must be non-negative")
+	}
+	if o.ExchangeInfoTTL < 0 {
+		return errors.NewValidationError("exchange_info_ttl", "must be non-negative")
+	}
+	if o.RateLimitWarnThreshold < 0 || o.RateLimitWarnThreshold > 1 {
+		return errors.NewValidationError("rate_limit_warn_threshold", "must be between 0 and 1")
+	}
+	for quote, max := range o.MaxOrderNotional {
+		if !max.IsPos() {
+			return errors.NewValidationError("max_order_notional", fmt.Sprintf("%s cap must be positive", quote))
+		}
+	}
+	for quote, max := range o.MaxPositionNotional {
+		if !max.IsPos() {
+			return errors.NewValidationError("max_position_notional", fmt.Sprintf("%s cap must be positive", quote))
+		}
+	}
 	if err := o.StreamConfig.Validate(); err != nil {
 		return fmt.Errorf("stream config: %w", err)
 	}
@@ -153,11 +388,19 @@ func (o Options) Validate() error {
 }
 
 // Client is the primary interface for interacting with exchanges.
-// All methods return normalized types from the market and order packages.
+// All methods return normalized types from the market, order, and account packages.
 type Client interface {
 	// Provider returns the exchange provider name.
 	Provider() Provider
 
+	// Capabilities returns the optional features the provider supports.
+	Capabilities() Capabilities
+
+	// RateLimitStatus returns the request-weight budget reported with the
+	// latest REST response, so callers can back off before ErrRateLimited.
+	// It is the zero value until the first response.
+	RateLimitStatus() RateLimitStatus
+
 	// Connect establishes connections to the exchange.
 	// Must be called before using stream methods.
 	Connect(ctx context.Context) error
@@ -166,55 +409,203 @@ type Client interface {
 	Close() error
 
 	// --- Market Data Streams ---
+	//
+	// Streams use Options.StreamConfig; opts override it for the one stream,
+	// e.g. stream.WithBufferSize for a burstier feed.
 
 	// TickerStream returns a stream of ticker updates.
-	TickerStream(symbol market.Symbol) stream.Stream[market.Ticker]
+	TickerStream(symbol market.Symbol, opts ...stream.Option) stream.Stream[market.Ticker]
+
+	// BookTickerStream returns a stream of best bid/offer updates, emitted on
+	// every change of the touch. Prefer it to OrderBookStream when only the
+	// top of the book is needed.
+	BookTickerStream(symbol market.Symbol, opts ...stream.Option) stream.Stream[market.BookTicker]
 
 	// OrderBookStream returns a stream of order book updates.
-	// Depth specifies the number of price levels (0 = full depth).
-	OrderBookStream(symbol market.Symbol, depth int) stream.Stream[market.OrderBook]
+	// Depth caps every emitted book, not just the first, to that many levels
+	// per side for the life of the stream (0 = full depth). A depth the
+	// provider does not serve is subscribed at the nearest one that covers it
+	// (see NativeBookDepth) and trimmed.
+	OrderBookStream(symbol market.Symbol, depth int, opts ...stream.Option) stream.Stream[market.OrderBook]
+
+	// OrderBookDiffStream returns a stream of incremental order book updates
+	// for maintaining a local book (see market.NewBookManager).
+	OrderBookDiffStream(symbol market.Symbol, opts ...stream.Option) stream.Stream[market.OrderBookDiff]
 
 	// TradeStream returns a stream of public trades.
-	TradeStream(symbol market.Symbol) stream.Stream[market.Trade]
+	TradeStream(symbol market.Symbol, opts ...stream.Option) stream.Stream[market.Trade]
 
 	// KlineStream returns a stream of kline/candlestick updates.
-	KlineStream(symbol market.Symbol, interval market.KlineInterval) stream.Stream[market.Kline]
+	KlineStream(symbol market.Symbol, interval market.KlineInterval, opts ...stream.Option) stream.Stream[market.Kline]
+
+	// --- Account Streams ---
+
+	// BalanceStream returns a stream of balance changes from the user data
+	// stream: the new balance of each asset whenever it changes, with the
+	// change and its reason when the exchange reports one.
+	BalanceStream(opts ...stream.Option) stream.Stream[order.BalanceUpdate]
+
+	// OrderStream returns a stream of order updates from the user data
+	// stream: the new state of each of the account's orders whenever it is
+	// placed, fills, is cancelled or expires.
+	OrderStream(opts ...stream.Option) stream.Stream[order.Order]
 
 	// --- REST API: Market Data ---
 
 	// GetTicker fetches the current ticker for a symbol.
 	GetTicker(ctx context.Context, symbol market.Symbol) (*market.Ticker, error)
 
-	// GetOrderBook fetches the current order book snapshot.
+	// GetTickers fetches the tickers of symbols, or of every symbol if symbols
+	// is empty, in a single call to the exchange's all-tickers endpoint.
+	// Symbols the exchange does not list are omitted.
+	GetTickers(ctx context.Context, symbols []market.Symbol) ([]market.Ticker, error)
+
+	// GetOrderBook fetches the current order book snapshot. Implementations
+	// guarantee bids descending and asks ascending by applying
+	// market.OrderBook.Sort to the exchange response.
 	GetOrderBook(ctx context.Context, symbol market.Symbol, depth int) (*market.OrderBook, error)
 
 	// GetTrades fetches recent public trades.
 	GetTrades(ctx context.Context, symbol market.Symbol, limit int) ([]market.Trade, error)
 
+	// GetTradesRange fetches the public trades executed within [start, end],
+	// paging through the exchange history as needed. Results are sorted by
+	// Timestamp.
+	GetTradesRange(ctx context.Context, symbol market.Symbol, start, end time.Time) ([]market.Trade, error)
+
 	// GetKlines fetches historical kline data.
 	GetKlines(ctx context.Context, symbol market.Symbol, interval market.KlineInterval, limit int) ([]market.Kline, error)
 
+	// GetKlinesRange fetches the klines opening within [start, end), paging
+	// through the exchange history as needed. Use IterateKlines for ranges too
+	// large to hold in memory.
+	GetKlinesRange(ctx context.Context, symbol market.Symbol, interval market.KlineInterval, start, end time.Time) ([]market.Kline, error)
+
+	// IterateKlines yields the klines opening within [start, end) in order,
+	// fetching them page by page so memory stays flat for large backfills.
+	// A fetch error, including ctx being cancelled, is yielded once and ends
+	// the iteration; breaking out of the loop stops further fetches.
+	IterateKlines(ctx context.Context, symbol market.Symbol, interval market.KlineInterval, start, end time.Time) iter.Seq2[market.Kline, error]
+
 	// GetSymbols fetches all available trading symbols.
 	GetSymbols(ctx context.Context) ([]market.Symbol, error)
 
+	// GetExchangeInfo returns the trading rules of every symbol. It is served
+	// from a cache refreshed once older than Options.ExchangeInfoTTL.
+	GetExchangeInfo(ctx context.Context) ([]market.SymbolInfo, error)
+
+	// GetSymbolInfo returns the trading rules of symbol, normalized like
+	// HasSymbol, from the exchange-info cache, so precision lookups before
+	// each order cost no request. The provider rounds and validates orders
+	// with it. Returns an error wrapping errors.ErrInvalidSymbol if the
+	// exchange does not list symbol.
+	GetSymbolInfo(ctx context.Context, symbol market.Symbol) (*market.SymbolInfo, error)
+
+	// HasSymbol reports whether the exchange lists symbol, checked against
+	// the exchange-info cache after normalizing symbol with market.NewSymbol,
+	// so "btcusdt" and "BTCUSDT" resolve alike. Use it before subscribing,
+	// since a stream of an unlisted symbol silently produces no data.
+	HasSymbol(ctx context.Context, symbol market.Symbol) (bool, error)
+
+	// RefreshExchangeInfo refetches the exchange-info cache now, e.g. after
+	// a listing announcement. On error the cached data is kept.
+	RefreshExchangeInfo(ctx context.Context) error
+
 	// --- REST API: Trading ---
 
-	// PlaceOrder places a new order.
+	// PlaceOrder places a new order. With Options.IdempotentOrders, retries
+	// after a timeout return the already accepted order rather than placing
+	// it twice. A GTD order's GoodTillDate must be after Options.Clock's
+	// current time (see order.Request.ValidateAt).
 	PlaceOrder(ctx context.Context, req *order.Request) (*order.Order, error)
 
 	// CancelOrder cancels an existing order.
+	// If the order already filled, was already cancelled, or is unknown to the
+	// exchange, the returned error wraps errors.ErrOrderNotActive so callers can
+	// treat the race as benign (see errors.IsOrderNotActive).
 	CancelOrder(ctx context.Context, req *order.CancelRequest) error
 
+	// CancelReplace cancels an order and places its replacement, returning the
+	// new order with ReplacesOrderID set. Providers with an atomic
+	// cancel-replace endpoint use it, so there is no window in which neither
+	// order is working; others fall back to CancelThenPlace.
+	CancelReplace(ctx context.Context, cancel *order.CancelRequest, place *order.Request) (*order.Order, error)
+
+	// SetCancelOnDisconnect arms the exchange's dead-man switch: if it is not
+	// renewed within window, the exchange cancels all open orders. The client
+	// renews it in the background while healthy, so the orders are only
+	// cancelled once the process dies or loses its connection. A zero window
+	// disarms it. Returns errors.ErrNotSupported if the exchange has no such
+	// endpoint (see Capabilities.SupportsDeadMan).
+	SetCancelOnDisconnect(ctx context.Context, window time.Duration) error
+
 	// GetOrder fetches an order by ID.
 	GetOrder(ctx context.Context, symbol market.Symbol, orderID string) (*order.Order, error)
 
+	// GetOrderByClientID fetches an order by the client ID it was placed
+	// with, e.g. after a timeout lost the exchange's order ID. Returns
+	// errors.ErrOrderNotFound if no order has that client ID.
+	GetOrderByClientID(ctx context.Context, symbol market.Symbol, clientID string) (*order.Order, error)
+
 	// GetOpenOrders fetches all open orders.
 	GetOpenOrders(ctx context.Context, symbol market.Symbol) ([]order.Order, error)
 
+	// GetAllOpenOrders fetches open orders across all symbols, e.g. to rebuild
+	// state at startup. It uses the exchange's all-symbols endpoint, which
+	// carries a much higher rate-limit weight than GetOpenOrders.
+	GetAllOpenOrders(ctx context.Context) ([]order.Order, error)
+
+	// GetOrderHistory fetches orders for symbol, including filled and cancelled
+	// ones, last updated within [start, end], paging through the exchange
+	// history as needed. A zero start or end leaves that bound open and
+	// limit <= 0 returns every order. Results are sorted by UpdatedAt.
+	GetOrderHistory(ctx context.Context, symbol market.Symbol, start, end time.Time, limit int) ([]order.Order, error)
+
+	// GetMyTrades fetches the account's own fills for symbol executed within
+	// [start, end], with commissions. A zero start or end leaves that bound
+	// open. Results are sorted by Timestamp.
+	GetMyTrades(ctx context.Context, symbol market.Symbol, start, end time.Time) ([]order.Fill, error)
+
+	// --- REST API: Convert ---
+
+	// GetConvertQuote requests an instant-convert quote for amount of the from asset.
+	// Returns errors.ErrNotSupported if the provider has no convert endpoint.
+	GetConvertQuote(ctx context.Context, from, to string, amount udecimal.Decimal) (*order.ConvertQuote, error)
+
+	// AcceptConvertQuote executes a previously fetched convert quote.
+	// Returns an error wrapping errors.ErrQuoteExpired if the quote is no longer valid.
+	AcceptConvertQuote(ctx context.Context, quoteID string) error
+
 	// --- REST API: Account ---
 
 	// GetBalance fetches account balances.
 	GetBalance(ctx context.Context) ([]order.Balance, error)
+
+	// GetAccountInfo fetches balances together with margin level, total value,
+	// and update time from the exchange account endpoint.
+	GetAccountInfo(ctx context.Context) (*account.Info, error)
+
+	// GetPositions fetches all open positions.
+	GetPositions(ctx context.Context) ([]account.Position, error)
+
+	// GetLeverageBrackets fetches the notional tiers of symbol's leverage
+	// schedule, sorted by NotionalFloor.
+	GetLeverageBrackets(ctx context.Context, symbol market.Symbol) ([]account.LeverageBracket, error)
+
+	// --- REST API: User Data Stream ---
+
+	// CreateListenKey creates a listen key for subscribing to the raw user
+	// data WebSocket, for callers that manage that connection themselves.
+	// The key expires after ListenKeyTTL unless kept alive (see
+	// RunListenKeyKeepAlive). Returns errors.ErrNotSupported if the exchange
+	// authenticates user streams otherwise (see Capabilities.SupportsListenKey).
+	CreateListenKey(ctx context.Context) (string, error)
+
+	// KeepAliveListenKey extends the validity of key by ListenKeyTTL.
+	KeepAliveListenKey(ctx context.Context, key string) error
+
+	// CloseListenKey invalidates key, closing its user data stream.
+	CloseListenKey(ctx context.Context, key string) error
 }
 
 // Factory creates a Client for a specific provider.
@@ -262,10 +653,20 @@ func New(p Provider, opts ...Option) (Client, error) {
 	for _, opt := range opts {
 		opt(&options)
 	}
+	if options.Clock == nil {
+		options.Clock = stream.SystemClock
+	}
+	if options.StreamConfig.Clock == nil {
+		options.StreamConfig.Clock = options.Clock
+	}
 
 	if err := options.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
 	}
 
-	return f(options)
+	c, err := f(options)
+	if err != nil {
+		return nil, err
+	}
+	return withGuards(depthClient{Client: c}, options), nil
 }
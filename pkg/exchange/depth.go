@@ -0,0 +1,64 @@
+package exchange
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pwnholic/clara/internal/infra"
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// bookDepths lists, in ascending order, the order book depths each provider
+// serves natively.
+var bookDepths = map[Provider][]int{
+	ProviderBinance: {5, 10, 20, 50, 100, 500, 1000, 5000},
+	ProviderBybit:   {1, 50, 200, 1000},
+}
+
+// BookDepths returns the order book depths p serves natively, ascending.
+func BookDepths(p Provider) []int {
+	return slices.Clone(bookDepths[p])
+}
+
+// NativeBookDepth returns the depth to request from p for a book of depth
+// levels per side: depth itself if p serves it, otherwise the nearest
+// native depth that covers it, or p's largest if none does. A depth of 0
+// (full depth) is returned as is. Adjustments are logged.
+func NativeBookDepth(p Provider, depth int) (int, error) {
+	if depth < 0 {
+		return 0, errors.NewValidationError("depth", "depth must not be negative")
+	}
+	depths, ok := bookDepths[p]
+	if !ok {
+		return 0, errors.NewValidationError("provider", fmt.Sprintf("invalid provider: %s", p))
+	}
+	if depth == 0 {
+		return 0, nil
+	}
+	i, found := slices.BinarySearch(depths, depth)
+	if found {
+		return depth, nil
+	}
+	native := depths[min(i, len(depths)-1)]
+	infra.Warn().Str("provider", string(p)).Int("depth", depth).Int("native_depth", native).
+		Msg("order book depth not supported by provider, using nearest")
+	return native, nil
+}
+
+// depthClient makes the depth of OrderBookStream authoritative in front of a
+// provider client.
+type depthClient struct {
+	Client
+}
+
+// OrderBookStream subscribes at the provider's nearest native depth and trims
+// every emitted book to depth levels per side.
+func (c depthClient) OrderBookStream(symbol market.Symbol, depth int, opts ...stream.Option) stream.Stream[market.OrderBook] {
+	native, err := NativeBookDepth(c.Provider(), depth)
+	if err != nil {
+		native = depth // Let the provider report it
+	}
+	return market.TrimBooks(c.Client.OrderBookStream(symbol, native, opts...), depth)
+}
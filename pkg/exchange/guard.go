@@ -0,0 +1,182 @@
+package exchange
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+// guardClient enforces the order guardrails of Options in front of a provider
+// client, rejecting orders before they reach the network.
+type guardClient struct {
+	Client
+	allow       map[market.Symbol]struct{} // nil = every symbol allowed
+	deny        map[market.Symbol]struct{}
+	maxOrder    map[string]udecimal.Decimal // Max order notional by quote asset
+	maxPosition map[string]udecimal.Decimal // Max position notional by quote asset
+}
+
+// withGuards wraps c if opts configure any guardrail.
+func withGuards(c Client, opts Options) Client {
+	if opts.SymbolAllowlist == nil && len(opts.SymbolDenylist) == 0 &&
+		len(opts.MaxOrderNotional) == 0 && len(opts.MaxPositionNotional) == 0 {
+		return c
+	}
+	g := &guardClient{
+		Client:      c,
+		deny:        symbolSet(opts.SymbolDenylist),
+		maxOrder:    opts.MaxOrderNotional,
+		maxPosition: opts.MaxPositionNotional,
+	}
+	if opts.SymbolAllowlist != nil {
+		g.allow = symbolSet(opts.SymbolAllowlist)
+	}
+	return g
+}
+
+// PlaceOrder rejects req if it breaks a guardrail.
+func (g *guardClient) PlaceOrder(ctx context.Context, req *order.Request) (*order.Order, error) {
+	if err := g.check(ctx, req); err != nil {
+		return nil, err
+	}
+	return g.Client.PlaceOrder(ctx, req)
+}
+
+// CancelReplace rejects the replacement if it breaks a guardrail; the
+// original order is left working.
+func (g *guardClient) CancelReplace(ctx context.Context, cancel *order.CancelRequest, place *order.Request) (*order.Order, error) {
+	if err := g.check(ctx, place); err != nil {
+		return nil, err
+	}
+	return g.Client.CancelReplace(ctx, cancel, place)
+}
+
+// check applies every guardrail to req.
+func (g *guardClient) check(ctx context.Context, req *order.Request) error {
+	if err := g.checkSymbol(req.Symbol); err != nil {
+		return err
+	}
+	return g.checkNotional(ctx, req)
+}
+
+// checkSymbol returns a ValidationError if symbol is denied or outside the allowlist.
+func (g *guardClient) checkSymbol(symbol market.Symbol) error {
+	s := market.NewSymbol(string(symbol))
+	if _, denied := g.deny[s]; denied {
+		return errors.NewValidationError("symbol", fmt.Sprintf("%s is in the symbol denylist", symbol))
+	}
+	if _, allowed := g.allow[s]; g.allow != nil && !allowed {
+		return errors.NewValidationError("symbol", fmt.Sprintf("%s is not in the symbol allowlist", symbol))
+	}
+	return nil
+}
+
+// checkNotional returns a ValidationError if req is worth more than the order
+// cap of its quote asset, or would leave a position worth more than the
+// position cap. Reduce-only orders skip the position check.
+func (g *guardClient) checkNotional(ctx context.Context, req *order.Request) error {
+	quote := req.Symbol.Quote()
+	maxOrder, hasOrderCap := g.maxOrder[quote]
+	maxPosition, hasPositionCap := g.maxPosition[quote]
+	if !hasOrderCap && !hasPositionCap {
+		return nil
+	}
+
+	price, err := g.guardPrice(ctx, req)
+	if err != nil {
+		return err
+	}
+	if notional := req.Quantity.Mul(price); hasOrderCap && notional.GreaterThan(maxOrder) {
+		return errors.NewValidationError("quantity",
+			fmt.Sprintf("order notional %s %s exceeds max %s", notional, quote, maxOrder))
+	}
+	if !hasPositionCap || req.ReduceOnly {
+		return nil
+	}
+
+	current, err := g.positionQty(ctx, req.Symbol)
+	if err != nil {
+		return err
+	}
+	after := current.Add(req.Quantity)
+	if req.Side == market.SideSell {
+		after = current.Sub(req.Quantity)
+	}
+	// Orders that shrink the position are always allowed, even above the cap.
+	if after.Abs().LessThanOrEqual(current.Abs()) {
+		return nil
+	}
+	if notional := after.Abs().Mul(price); notional.GreaterThan(maxPosition) {
+		return errors.NewValidationError("quantity",
+			fmt.Sprintf("position notional %s %s after the order exceeds max %s", notional, quote, maxPosition))
+	}
+	return nil
+}
+
+// guardPrice returns the price req is valued at: its limit or stop price, or
+// for market orders the touch it would take (ask for buys, bid for sells).
+func (g *guardClient) guardPrice(ctx context.Context, req *order.Request) (udecimal.Decimal, error) {
+	switch {
+	case req.Price.IsPos():
+		return req.Price, nil
+	case req.StopPrice.IsPos():
+		return req.StopPrice, nil
+	}
+	t, err := g.GetTicker(ctx, req.Symbol)
+	if err != nil {
+		return udecimal.Decimal{}, fmt.Errorf("price %s for notional check: %w", req.Symbol, err)
+	}
+	price := t.AskPrice
+	if req.Side == market.SideSell {
+		price = t.BidPrice
+	}
+	if !price.IsPos() {
+		price = t.LastPrice
+	}
+	return price, nil
+}
+
+// positionQty fetches the open position in symbol, negative when short.
+// Providers without positions (spot) report errors.ErrNotSupported, which
+// counts as flat.
+func (g *guardClient) positionQty(ctx context.Context, symbol market.Symbol) (udecimal.Decimal, error) {
+	positions, err := g.GetPositions(ctx)
+	if stderrors.Is(err, errors.ErrNotSupported) {
+		return udecimal.Decimal{}, nil
+	}
+	if err != nil {
+		return udecimal.Decimal{}, fmt.Errorf("position %s for notional check: %w", symbol, err)
+	}
+	for _, p := range positions {
+		if p.Symbol == symbol && p.IsOpen() {
+			if p.IsShort() {
+				return p.AbsQty().Neg(), nil
+			}
+			return p.AbsQty(), nil
+		}
+	}
+	return udecimal.Decimal{}, nil
+}
+
+// symbolSet returns the normalized symbols as a set.
+func symbolSet(symbols []market.Symbol) map[market.Symbol]struct{} {
+	set := make(map[market.Symbol]struct{}, len(symbols))
+	for _, s := range symbols {
+		set[market.NewSymbol(string(s))] = struct{}{}
+	}
+	return set
+}
+
+// setNotionalCap sets the cap of quote in caps, allocating caps if needed.
+func setNotionalCap(caps *map[string]udecimal.Decimal, quote string, max udecimal.Decimal) {
+	if *caps == nil {
+		*caps = make(map[string]udecimal.Decimal)
+	}
+	(*caps)[strings.ToUpper(quote)] = max
+}
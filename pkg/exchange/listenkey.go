@@ -0,0 +1,56 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+const (
+	// ListenKeyTTL is how long a listen key stays valid without a keepalive.
+	ListenKeyTTL = 60 * time.Minute
+
+	// ListenKeyKeepAliveInterval is how often RunListenKeyKeepAlive extends a
+	// key.
+	ListenKeyKeepAliveInterval = 30 * time.Minute
+
+	// listenKeyRetryInterval is how soon a failed keepalive is retried.
+	listenKeyRetryInterval = time.Minute
+)
+
+// RunListenKeyKeepAlive extends key every ListenKeyKeepAliveInterval until
+// ctx is cancelled, then returns ctx.Err(). A failed keepalive is retried
+// every minute; once the key would expire before the next retry its stream is
+// as good as dead, and an error wrapping errors.ErrDisconnected is returned
+// so the caller can create a new key and reconnect. The key is not closed on
+// return. Intervals are measured on clock (nil = stream.SystemClock).
+func RunListenKeyKeepAlive(ctx context.Context, c Client, key string, clock stream.Clock) error {
+	if clock == nil {
+		clock = stream.SystemClock
+	}
+	lastOK := clock.Now()
+	wait := ListenKeyKeepAliveInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(wait):
+		}
+
+		err := c.KeepAliveListenKey(ctx, key)
+		switch {
+		case err == nil:
+			lastOK = clock.Now()
+			wait = ListenKeyKeepAliveInterval
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case clock.Now().Sub(lastOK) >= ListenKeyTTL-listenKeyRetryInterval:
+			return fmt.Errorf("%w: listen key expired: %w", errors.ErrDisconnected, err)
+		default:
+			wait = listenKeyRetryInterval
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// keepAliveClient records when KeepAliveListenKey is called and answers with
+// the next of results, then with nil.
+type keepAliveClient struct {
+	Client
+	clock   stream.Clock
+	results []error
+	calls   chan time.Time
+}
+
+func (c *keepAliveClient) KeepAliveListenKey(ctx context.Context, key string) error {
+	c.calls <- c.clock.Now()
+	if len(c.results) == 0 {
+		return nil
+	}
+	err := c.results[0]
+	c.results = c.results[1:]
+	return err
+}
+
+func TestRunListenKeyKeepAlive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := stream.NewManualClock(start)
+	failures := make([]error, 100)
+	for i := range failures[1:] {
+		failures[i+1] = errors.ErrTimeout
+	}
+	c := &keepAliveClient{clock: clock, results: failures, calls: make(chan time.Time, 1)}
+	done := make(chan error, 1)
+	go func() { done <- RunListenKeyKeepAlive(ctx, c, "key", clock) }()
+
+	// advance moves the clock by d once the keep-alive waits on it.
+	advance := func(d time.Duration) {
+		t.Helper()
+		if err := clock.BlockUntil(ctx, 1); err != nil {
+			t.Fatalf("keep-alive not waiting: %v", err)
+		}
+		clock.Advance(d)
+	}
+	expectCall := func(at time.Duration) {
+		t.Helper()
+		select {
+		case got := <-c.calls:
+			if want := start.Add(at); !got.Equal(want) {
+				t.Fatalf("keepalive at %v, want %v", got.Sub(start), at)
+			}
+		case <-ctx.Done():
+			t.Fatalf("no keepalive at %v", at)
+		}
+	}
+
+	advance(ListenKeyKeepAliveInterval - time.Second)
+	select {
+	case <-c.calls:
+		t.Fatal("keepalive before ListenKeyKeepAliveInterval")
+	default:
+	}
+	advance(time.Second)
+	expectCall(ListenKeyKeepAliveInterval) // Succeeds: the key is valid until 90m
+
+	// Every later keepalive fails and is retried each minute until the key
+	// would expire before the next retry.
+	advance(ListenKeyKeepAliveInterval)
+	expectCall(60 * time.Minute)
+	for at := 61 * time.Minute; at <= 89*time.Minute; at += time.Minute {
+		advance(time.Minute)
+		expectCall(at)
+	}
+	select {
+	case err := <-done:
+		if !stderrors.Is(err, errors.ErrDisconnected) || !stderrors.Is(err, errors.ErrTimeout) {
+			t.Fatalf("err = %v, want ErrDisconnected wrapping ErrTimeout", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("keep-alive did not give up on the expiring key")
+	}
+}
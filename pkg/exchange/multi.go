@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+)
+
+// ProviderErrors holds the errors of the venues a MultiClient call failed on.
+// errors.Is and errors.As see each of them.
+type ProviderErrors map[Provider]error
+
+// Error implements error, listing the failures by provider.
+func (e ProviderErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, p := range slices.Sorted(maps.Keys(e)) {
+		parts = append(parts, fmt.Sprintf("%s: %v", p, e[p]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Unwrap returns the individual errors.
+func (e ProviderErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, p := range slices.Sorted(maps.Keys(e)) {
+		errs = append(errs, e[p])
+	}
+	return errs
+}
+
+// MultiClient gives access to the clients of several venues and queries them
+// together for cross-venue work such as arbitrage and order routing.
+//
+// Aggregate calls query every venue concurrently with the same context and
+// return what the venues that answered reported, together with a
+// ProviderErrors for those that failed. The error is nil only if every venue
+// succeeded, so a non-nil error does not by itself invalidate the result.
+type MultiClient struct {
+	clients map[Provider]Client
+}
+
+// NewMulti returns a MultiClient over clients.
+func NewMulti(clients map[Provider]Client) MultiClient {
+	return MultiClient{clients: maps.Clone(clients)}
+}
+
+// Client returns the client of provider.
+func (m MultiClient) Client(provider Provider) (Client, bool) {
+	c, ok := m.clients[provider]
+	return c, ok
+}
+
+// Providers returns the providers of the clients, sorted.
+func (m MultiClient) Providers() []Provider {
+	return slices.Sorted(maps.Keys(m.clients))
+}
+
+// GetBestTicker fetches the ticker of symbol from every venue and returns the
+// one with the tightest spread, and its provider. Venues whose ticker lacks a
+// bid or ask are skipped; if none is left the result is nil and the error
+// wraps errors.ErrNotFound.
+func (m MultiClient) GetBestTicker(ctx context.Context, symbol market.Symbol) (Provider, *market.Ticker, error) {
+	tickers, err := fanOut(ctx, m.clients, func(ctx context.Context, c Client) (*market.Ticker, error) {
+		return c.GetTicker(ctx, symbol)
+	})
+
+	var (
+		best     *market.Ticker
+		provider Provider
+	)
+	for _, p := range slices.Sorted(maps.Keys(tickers)) {
+		t := tickers[p]
+		if t == nil || !t.BidPrice.IsPos() || !t.AskPrice.IsPos() {
+			continue
+		}
+		if best == nil || t.Spread().LessThan(best.Spread()) {
+			best, provider = t, p
+		}
+	}
+	if best == nil {
+		notFound := fmt.Errorf("%w: no venue quotes %s", errors.ErrNotFound, symbol)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w (%w)", notFound, err)
+		}
+		return "", nil, notFound
+	}
+	return provider, best, err
+}
+
+// GetBalancesAll fetches the balances of every venue.
+func (m MultiClient) GetBalancesAll(ctx context.Context) (map[Provider][]order.Balance, error) {
+	return fanOut(ctx, m.clients, func(ctx context.Context, c Client) ([]order.Balance, error) {
+		return c.GetBalance(ctx)
+	})
+}
+
+// fanOut calls fn on every client concurrently and collects the results of
+// those that succeeded. The error is a ProviderErrors, or nil if none failed.
+func fanOut[T any](ctx context.Context, clients map[Provider]Client, fn func(ctx context.Context, c Client) (T, error)) (map[Provider]T, error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[Provider]T, len(clients))
+		errs    = make(ProviderErrors)
+	)
+	for p, c := range clients {
+		wg.Go(func() {
+			v, err := fn(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[p] = err
+				return
+			}
+			results[p] = v
+		})
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
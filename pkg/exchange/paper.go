@@ -0,0 +1,761 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/account"
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
+)
+
+// paperBookDepth is the number of levels fetched to simulate a fill.
+const paperBookDepth = 100
+
+// paperClient simulates trading on top of a live client. Market data calls go
+// to the embedded client; trading and account calls are served from local state.
+type paperClient struct {
+	Client
+	clock stream.Clock
+
+	mu          sync.Mutex
+	seq         int64
+	tradeSeq    int64
+	orders      []*order.Order
+	fills       []order.Fill
+	balances    map[string]*order.Balance
+	positions   map[market.Symbol]*account.Position
+	balanceFeed paperFeed[order.BalanceUpdate]
+	orderFeed   paperFeed[order.Order]
+}
+
+// NewPaper returns a Client for dry runs. Streams and market data requests are
+// delegated to underlying, while orders never reach the exchange: PlaceOrder
+// fills against the live order book (see market.OrderBook.MarketImpact) and
+// the resulting balances and positions are served by GetBalance,
+// GetAccountInfo and GetPositions. BalanceStream and OrderStream report the
+// simulated balances and orders instead of the live account's.
+//
+// Market orders and the marketable part of limit orders fill immediately.
+// The rest of a GTC, GTX or GTD limit order rests with its funds locked and is
+// matched against a fresh book whenever orders or fills for its symbol are
+// queried; GTD orders expire at the first such query past GoodTillDate.
+// Each resting order sees the whole book, and no fees are charged. Trigger
+// orders and convert are not simulated and return errors.ErrNotSupported.
+//
+// Base and quote assets come from market.Symbol.Base and Quote.
+func NewPaper(underlying Client, startingBalances []order.Balance, opts ...PaperOption) Client {
+	p := &paperClient{
+		Client:    underlying,
+		clock:     stream.SystemClock,
+		balances:  make(map[string]*order.Balance, len(startingBalances)),
+		positions: make(map[market.Symbol]*account.Position),
+	}
+	for _, b := range startingBalances {
+		bal := b
+		p.balances[b.Asset] = &bal
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// PaperOption configures a paper client.
+type PaperOption func(*paperClient)
+
+// WithPaperClock stamps simulated orders, fills and positions with c instead
+// of the system clock, e.g. to replay a backtest.
+func WithPaperClock(c stream.Clock) PaperOption {
+	return func(p *paperClient) {
+		p.clock = c
+	}
+}
+
+// Capabilities reports the underlying provider's capabilities narrowed to
+// what paper trading simulates.
+func (p *paperClient) Capabilities() Capabilities {
+	caps := p.Client.Capabilities()
+	caps.SupportsOCO = false
+	caps.SupportsWSOrders = false
+	caps.SupportsHedgeMode = false
+	caps.SupportsConvert = false
+	caps.SupportsCancelReplace = true
+	caps.SupportsDeadMan = true
+	caps.SupportsListenKey = false
+	caps.SupportedOrderTypes = []order.Type{order.TypeLimit, order.TypeMarket}
+	return caps
+}
+
+// SetCancelOnDisconnect is a no-op: simulated orders live in the process and
+// cannot outlive it.
+func (p *paperClient) SetCancelOnDisconnect(ctx context.Context, window time.Duration) error {
+	if window < 0 {
+		return errors.NewValidationError("window", "must be non-negative")
+	}
+	return nil
+}
+
+// PlaceOrder simulates the order against the current order book.
+func (p *paperClient) PlaceOrder(ctx context.Context, req *order.Request) (*order.Order, error) {
+	if err := req.ValidateAt(p.clock.Now()); err != nil {
+		return nil, err
+	}
+	if req.Type.IsTrigger() {
+		return nil, fmt.Errorf("%w: paper trading does not simulate %s orders", errors.ErrNotSupported, req.Type)
+	}
+
+	book, err := p.Client.GetOrderBook(ctx, req.Symbol, paperBookDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if req.ReduceOnly {
+		if err := p.checkReduceOnly(req); err != nil {
+			return nil, err
+		}
+	}
+
+	now := p.clock.Now()
+	p.seq++
+	o := &order.Order{
+		ID:           fmt.Sprintf("paper-%d", p.seq),
+		ClientID:     req.ClientID,
+		Symbol:       req.Symbol,
+		Side:         req.Side,
+		Type:         req.Type,
+		Status:       order.StatusNew,
+		Price:        req.Price,
+		Quantity:     req.Quantity,
+		IcebergQty:   req.IcebergQty,
+		TimeInForce:  req.TimeInForce,
+		GoodTillDate: req.GoodTillDate,
+		ReduceOnly:   req.ReduceOnly,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	avg, filled := paperMatch(book, o.Side, o.Quantity, o.Price)
+	if o.Type == order.TypeMarket && filled.IsZero() {
+		return nil, errors.NewValidationError("orderbook", "no liquidity")
+	}
+	switch {
+	case o.TimeInForce == order.GTX && filled.IsPos():
+		// A post-only order that would take liquidity is rejected by the matching engine.
+		o.Status = order.StatusExpired
+		p.addOrder(o)
+		return cloneOrder(o), nil
+	case o.TimeInForce == order.FOK && filled.LessThan(o.Quantity):
+		o.Status = order.StatusExpired
+		p.addOrder(o)
+		return cloneOrder(o), nil
+	}
+
+	rests := o.Type == order.TypeLimit && (o.TimeInForce == order.GTC || o.TimeInForce == order.GTX || o.TimeInForce == order.GTD)
+	var restQty udecimal.Decimal
+	if rests {
+		restQty = o.Quantity.Sub(filled)
+	}
+	if err := p.checkFunds(o, filled.Mul(avg), filled, restQty); err != nil {
+		return nil, err
+	}
+
+	if filled.IsPos() {
+		p.fill(o, filled, avg, false, now)
+	}
+	if o.RemainingQty().IsPos() {
+		if rests {
+			p.lock(o, o.RemainingQty())
+		} else {
+			o.Status = order.StatusExpired
+		}
+	}
+	p.addOrder(o)
+	return cloneOrder(o), nil
+}
+
+// CancelOrder cancels a resting simulated order and releases its locked funds.
+func (p *paperClient) CancelOrder(ctx context.Context, req *order.CancelRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, o := range p.orders {
+		if o.Symbol != req.Symbol || (req.OrderID != "" && o.ID != req.OrderID) || (req.OrderID == "" && o.ClientID != req.ClientID) {
+			continue
+		}
+		if !o.IsOpen() {
+			return fmt.Errorf("%w: order %s is %s", errors.ErrOrderNotActive, o.ID, o.Status)
+		}
+		p.unlock(o, o.RemainingQty())
+		o.Status = order.StatusCancelled
+		o.UpdatedAt = p.clock.Now()
+		p.orderFeed.publish(*cloneOrder(o))
+		return nil
+	}
+	return fmt.Errorf("%w: order %s%s not found", errors.ErrOrderNotActive, req.OrderID, req.ClientID)
+}
+
+// CancelReplace cancels then places. Nothing else trades on the paper book, so
+// the gap between the two is harmless.
+func (p *paperClient) CancelReplace(ctx context.Context, cancel *order.CancelRequest, place *order.Request) (*order.Order, error) {
+	o, err := CancelThenPlace(ctx, p, cancel, place)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, stored := range p.orders {
+		if stored.ID == o.ID {
+			stored.ReplacesOrderID = o.ReplacesOrderID
+		}
+	}
+	return o, nil
+}
+
+// GetOrder returns a simulated order after matching resting orders for symbol.
+func (p *paperClient) GetOrder(ctx context.Context, symbol market.Symbol, orderID string) (*order.Order, error) {
+	if err := p.sweep(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, o := range p.orders {
+		if o.Symbol == symbol && o.ID == orderID {
+			return cloneOrder(o), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errors.ErrOrderNotFound, orderID)
+}
+
+// GetOrderByClientID returns the most recent simulated order placed with
+// clientID after matching resting orders for symbol.
+func (p *paperClient) GetOrderByClientID(ctx context.Context, symbol market.Symbol, clientID string) (*order.Order, error) {
+	if err := p.sweep(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, o := range slices.Backward(p.orders) {
+		if o.Symbol == symbol && clientID != "" && o.ClientID == clientID {
+			return cloneOrder(o), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: client ID %s", errors.ErrOrderNotFound, clientID)
+}
+
+// GetOpenOrders returns the resting simulated orders for symbol, oldest first.
+func (p *paperClient) GetOpenOrders(ctx context.Context, symbol market.Symbol) ([]order.Order, error) {
+	if err := p.sweep(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var open []order.Order
+	for _, o := range p.orders {
+		if o.Symbol == symbol && o.IsOpen() {
+			open = append(open, *cloneOrder(o))
+		}
+	}
+	return open, nil
+}
+
+// GetAllOpenOrders returns the open simulated orders of every symbol after
+// sweeping each of them.
+func (p *paperClient) GetAllOpenOrders(ctx context.Context) ([]order.Order, error) {
+	p.mu.Lock()
+	var symbols []market.Symbol
+	for _, o := range p.orders {
+		if o.IsOpen() && !slices.Contains(symbols, o.Symbol) {
+			symbols = append(symbols, o.Symbol)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, symbol := range symbols {
+		if err := p.sweep(ctx, symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var open []order.Order
+	for _, o := range p.orders {
+		if o.IsOpen() {
+			open = append(open, *cloneOrder(o))
+		}
+	}
+	return open, nil
+}
+
+// GetOrderHistory returns the simulated orders for symbol updated within
+// [start, end], sorted by UpdatedAt.
+func (p *paperClient) GetOrderHistory(ctx context.Context, symbol market.Symbol, start, end time.Time, limit int) ([]order.Order, error) {
+	if err := p.sweep(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var history []order.Order
+	for _, o := range p.orders {
+		if o.Symbol != symbol || (!start.IsZero() && o.UpdatedAt.Before(start)) || (!end.IsZero() && o.UpdatedAt.After(end)) {
+			continue
+		}
+		history = append(history, *cloneOrder(o))
+	}
+	slices.SortStableFunc(history, func(a, b order.Order) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	if limit > 0 && len(history) > limit {
+		history = history[:limit]
+	}
+	return history, nil
+}
+
+// GetMyTrades returns the simulated fills for symbol executed within [start, end].
+func (p *paperClient) GetMyTrades(ctx context.Context, symbol market.Symbol, start, end time.Time) ([]order.Fill, error) {
+	if err := p.sweep(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var fills []order.Fill
+	for _, f := range p.fills {
+		if f.Symbol != symbol || (!start.IsZero() && f.Timestamp.Before(start)) || (!end.IsZero() && f.Timestamp.After(end)) {
+			continue
+		}
+		fills = append(fills, f)
+	}
+	return fills, nil
+}
+
+// GetConvertQuote is not simulated.
+func (p *paperClient) GetConvertQuote(ctx context.Context, from, to string, amount udecimal.Decimal) (*order.ConvertQuote, error) {
+	return nil, fmt.Errorf("%w: paper trading does not simulate convert", errors.ErrNotSupported)
+}
+
+// AcceptConvertQuote is not simulated.
+func (p *paperClient) AcceptConvertQuote(ctx context.Context, quoteID string) error {
+	return fmt.Errorf("%w: paper trading does not simulate convert", errors.ErrNotSupported)
+}
+
+// CreateListenKey is not simulated: the raw user data stream would carry the
+// live account's events. Use BalanceStream and OrderStream instead.
+func (p *paperClient) CreateListenKey(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("%w: paper trading has no listen keys", errors.ErrNotSupported)
+}
+
+// KeepAliveListenKey is not simulated.
+func (p *paperClient) KeepAliveListenKey(ctx context.Context, key string) error {
+	return fmt.Errorf("%w: paper trading has no listen keys", errors.ErrNotSupported)
+}
+
+// CloseListenKey is not simulated.
+func (p *paperClient) CloseListenKey(ctx context.Context, key string) error {
+	return fmt.Errorf("%w: paper trading has no listen keys", errors.ErrNotSupported)
+}
+
+// GetBalance returns the simulated balances sorted by asset.
+func (p *paperClient) GetBalance(ctx context.Context) ([]order.Balance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.balanceList(), nil
+}
+
+// GetAccountInfo returns the simulated balances. TotalValue and MarginLevel
+// are left zero.
+func (p *paperClient) GetAccountInfo(ctx context.Context) (*account.Info, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return &account.Info{
+		Balances:   p.balanceList(),
+		UpdateTime: p.clock.Now(),
+	}, nil
+}
+
+// GetPositions returns the open simulated positions sorted by symbol, marked to
+// the last traded price of the underlying client.
+func (p *paperClient) GetPositions(ctx context.Context) ([]account.Position, error) {
+	p.mu.Lock()
+	var positions []account.Position
+	for _, pos := range p.positions {
+		if pos.IsOpen() {
+			positions = append(positions, *pos)
+		}
+	}
+	p.mu.Unlock()
+
+	slices.SortFunc(positions, func(a, b account.Position) int {
+		return strings.Compare(string(a.Symbol), string(b.Symbol))
+	})
+	for i := range positions {
+		ticker, err := p.Client.GetTicker(ctx, positions[i].Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("mark %s: %w", positions[i].Symbol, err)
+		}
+		positions[i].MarkPrice = ticker.LastPrice
+		positions[i].UnrealizedPnL = ticker.LastPrice.Sub(positions[i].EntryPrice).Mul(positions[i].Quantity)
+	}
+	return positions, nil
+}
+
+// sweep matches the resting orders for symbol against a fresh order book.
+func (p *paperClient) sweep(ctx context.Context, symbol market.Symbol) error {
+	p.mu.Lock()
+	resting := slices.ContainsFunc(p.orders, func(o *order.Order) bool {
+		return o.Symbol == symbol && o.IsOpen()
+	})
+	p.mu.Unlock()
+	if !resting {
+		return nil
+	}
+
+	book, err := p.Client.GetOrderBook(ctx, symbol, paperBookDepth)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+	for _, o := range p.orders {
+		if o.Symbol != symbol || !o.IsOpen() {
+			continue
+		}
+		if o.TimeInForce == order.GTD && !now.Before(o.GoodTillDate) {
+			p.unlock(o, o.RemainingQty())
+			o.Status = order.StatusExpired
+			o.UpdatedAt = now
+			p.orderFeed.publish(*cloneOrder(o))
+			continue
+		}
+		avg, filled := paperMatch(book, o.Side, o.RemainingQty(), o.Price)
+		if filled.IsZero() {
+			continue
+		}
+		p.unlock(o, filled)
+		p.fill(o, filled, avg, true, now)
+		p.orderFeed.publish(*cloneOrder(o))
+	}
+	return nil
+}
+
+// checkReduceOnly rejects a reduce-only request that would open or grow a position.
+func (p *paperClient) checkReduceOnly(req *order.Request) error {
+	var held udecimal.Decimal
+	if pos, ok := p.positions[req.Symbol]; ok {
+		held = pos.Quantity
+	}
+	if (req.Side == market.SideSell && !held.IsPos()) || (req.Side == market.SideBuy && !held.IsNeg()) {
+		return fmt.Errorf("%w: reduce-only %s would not reduce the %s position", errors.ErrInvalidOrder, req.Side, req.Symbol)
+	}
+	if req.Quantity.GreaterThan(held.Abs()) {
+		return fmt.Errorf("%w: reduce-only quantity %s exceeds position %s", errors.ErrInvalidOrder, req.Quantity, held.Abs())
+	}
+	return nil
+}
+
+// checkFunds verifies the free balance covers the immediate fill (cost in
+// quote, qty in base) plus restQty to be locked at the limit price.
+func (p *paperClient) checkFunds(o *order.Order, cost, qty, restQty udecimal.Decimal) error {
+	asset, need := o.Symbol.Base(), qty.Add(restQty)
+	if o.Side == market.SideBuy {
+		asset, need = o.Symbol.Quote(), cost.Add(restQty.Mul(o.Price))
+	}
+	free := p.balance(asset).Free
+	if need.GreaterThan(free) {
+		return fmt.Errorf("%w: need %s %s, have %s", errors.ErrInsufficientBalance, need, asset, free)
+	}
+	return nil
+}
+
+// fill executes qty of o at price, settling balances and the position.
+// isMaker is set for resting orders.
+func (p *paperClient) fill(o *order.Order, qty, price udecimal.Decimal, isMaker bool, now time.Time) {
+	base, quote := p.balance(o.Symbol.Base()), p.balance(o.Symbol.Quote())
+	notional := qty.Mul(price)
+	signed := qty
+	if o.Side == market.SideBuy {
+		quote.Free = quote.Free.Sub(notional)
+		base.Free = base.Free.Add(qty)
+		p.publishBalance(base, qty, order.BalanceChangeTrade, now)
+		p.publishBalance(quote, notional.Neg(), order.BalanceChangeTrade, now)
+	} else {
+		base.Free = base.Free.Sub(qty)
+		quote.Free = quote.Free.Add(notional)
+		signed = qty.Neg()
+		p.publishBalance(base, qty.Neg(), order.BalanceChangeTrade, now)
+		p.publishBalance(quote, notional, order.BalanceChangeTrade, now)
+	}
+
+	total := o.ExecutedNotional().Add(notional)
+	o.ExecutedQty = o.ExecutedQty.Add(qty)
+	if avg, err := total.Div(o.ExecutedQty); err == nil {
+		o.AvgPrice = avg
+	}
+	o.Status = order.StatusPartiallyFilled
+	if !o.RemainingQty().IsPos() {
+		o.Status = order.StatusFilled
+	}
+	o.UpdatedAt = now
+
+	p.tradeSeq++
+	f := order.Fill{
+		ID:        fmt.Sprintf("paper-trade-%d", p.tradeSeq),
+		OrderID:   o.ID,
+		Symbol:    o.Symbol,
+		Side:      o.Side,
+		Price:     price,
+		Qty:       qty,
+		IsMaker:   isMaker,
+		Timestamp: now,
+	}
+	o.Fills = append(o.Fills, f)
+	p.fills = append(p.fills, f)
+	p.updatePosition(o.Symbol, signed, price, now)
+}
+
+// lock moves the funds backing qty of a resting order from free to locked.
+func (p *paperClient) lock(o *order.Order, qty udecimal.Decimal) {
+	b, amount := p.reserved(o, qty)
+	b.Free = b.Free.Sub(amount)
+	b.Locked = b.Locked.Add(amount)
+	p.publishBalance(b, udecimal.Decimal{}, order.BalanceChangeOrder, p.clock.Now())
+}
+
+// unlock releases the funds backing qty of a resting order.
+func (p *paperClient) unlock(o *order.Order, qty udecimal.Decimal) {
+	b, amount := p.reserved(o, qty)
+	b.Locked = b.Locked.Sub(amount)
+	b.Free = b.Free.Add(amount)
+	p.publishBalance(b, udecimal.Decimal{}, order.BalanceChangeOrder, p.clock.Now())
+}
+
+// reserved returns the balance and amount that back qty of a resting order.
+func (p *paperClient) reserved(o *order.Order, qty udecimal.Decimal) (*order.Balance, udecimal.Decimal) {
+	if o.Side == market.SideBuy {
+		return p.balance(o.Symbol.Quote()), qty.Mul(o.Price)
+	}
+	return p.balance(o.Symbol.Base()), qty
+}
+
+// updatePosition applies a signed fill to the one-way position for symbol.
+// Increasing fills move the entry to the weighted average; reducing fills
+// realize PnL against the entry, and a fill that flips the side re-enters at price.
+func (p *paperClient) updatePosition(symbol market.Symbol, qty, price udecimal.Decimal, now time.Time) {
+	pos, ok := p.positions[symbol]
+	if !ok {
+		pos = &account.Position{Symbol: symbol, Side: account.PositionSideBoth}
+		p.positions[symbol] = pos
+	}
+
+	if pos.Quantity.IsZero() || pos.Quantity.Sign() == qty.Sign() {
+		total := pos.AbsQty().Add(qty.Abs())
+		if entry, err := pos.EntryValue().Add(qty.Abs().Mul(price)).Div(total); err == nil {
+			pos.EntryPrice = entry
+		}
+		pos.Quantity = pos.Quantity.Add(qty)
+	} else {
+		closed := udecimal.Min(pos.AbsQty(), qty.Abs())
+		pnl := price.Sub(pos.EntryPrice).Mul(closed)
+		if pos.Quantity.IsNeg() {
+			pnl = pnl.Neg()
+		}
+		pos.RealizedPnL = pos.RealizedPnL.Add(pnl)
+		prev := pos.Quantity
+		pos.Quantity = pos.Quantity.Add(qty)
+		switch {
+		case pos.Quantity.IsZero():
+			pos.EntryPrice = udecimal.Decimal{}
+		case pos.Quantity.Sign() != prev.Sign():
+			pos.EntryPrice = price
+		}
+	}
+	pos.MarkPrice = price
+	pos.UpdateTime = now
+}
+
+// BalanceStream streams the simulated balance changes caused by fills and by
+// funds locked or released for resting orders.
+func (p *paperClient) BalanceStream(opts ...stream.Option) stream.Stream[order.BalanceUpdate] {
+	return newPaperStream(p, &p.balanceFeed, "balance", opts)
+}
+
+// OrderStream streams the simulated orders as they are placed, filled,
+// cancelled or expire. Resting orders only fill when their symbol is swept
+// (see NewPaper), so that is also when their fills are streamed.
+func (p *paperClient) OrderStream(opts ...stream.Option) stream.Stream[order.Order] {
+	return newPaperStream(p, &p.orderFeed, "order", opts)
+}
+
+// publishBalance sends the current state of b to every balance stream.
+// p.mu must be held.
+func (p *paperClient) publishBalance(b *order.Balance, delta udecimal.Decimal, reason order.BalanceChangeReason, now time.Time) {
+	p.balanceFeed.publish(order.BalanceUpdate{Balance: *b, Delta: delta, Reason: reason, Timestamp: now})
+}
+
+// addOrder stores a new order and streams it. p.mu must be held.
+func (p *paperClient) addOrder(o *order.Order) {
+	p.orders = append(p.orders, o)
+	p.orderFeed.publish(*cloneOrder(o))
+}
+
+// paperFeed fans simulated updates of one type out to the paper client's
+// streams. Its subscribers are guarded by paperClient.mu.
+type paperFeed[T any] struct {
+	subs []*paperStream[T]
+}
+
+// publish sends v to every subscriber. paperClient.mu must be held.
+func (f *paperFeed[T]) publish(v T) {
+	for _, s := range f.subs {
+		select {
+		case s.updates <- v:
+		default:
+			// Subscriber behind; like any stream, it drops the update.
+		}
+	}
+}
+
+// paperStream is a subscriber to a paper client's feed.
+type paperStream[T any] struct {
+	*stream.BaseStream[T]
+	paper   *paperClient
+	feed    *paperFeed[T]
+	updates chan T // Never closed; emitted from the stream's goroutine
+}
+
+// newPaperStream returns a stream of feed named name.
+func newPaperStream[T any](p *paperClient, feed *paperFeed[T], name string, opts []stream.Option) stream.Stream[T] {
+	cfg := stream.DefaultConfig().With(opts...)
+	cfg.Clock = p.clock
+	s := &paperStream[T]{
+		BaseStream: stream.NewBaseStream[T](cfg),
+		paper:      p,
+		feed:       feed,
+		updates:    make(chan T, max(cfg.BufferSize, 1)),
+	}
+	s.SetLabels(stream.Labels{Provider: "paper", Stream: name})
+	return s
+}
+
+// Subscribe registers the stream with its feed.
+func (s *paperStream[T]) Subscribe(ctx context.Context) (<-chan T, error) {
+	out := s.DataChannel()
+	err := s.Start(ctx, func(ctx context.Context) error {
+		s.paper.mu.Lock()
+		s.feed.subs = append(s.feed.subs, s)
+		s.paper.mu.Unlock()
+		defer func() {
+			s.paper.mu.Lock()
+			s.feed.subs = slices.DeleteFunc(s.feed.subs, func(sub *paperStream[T]) bool { return sub == s })
+			s.paper.mu.Unlock()
+		}()
+		s.MarkConnected()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case v := <-s.updates:
+				s.Emit(v)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe stops the stream.
+func (s *paperStream[T]) Unsubscribe(ctx context.Context) error {
+	return s.Stop()
+}
+
+// balance returns the balance for asset, creating an empty one if needed.
+func (p *paperClient) balance(asset string) *order.Balance {
+	b, ok := p.balances[asset]
+	if !ok {
+		b = &order.Balance{Asset: asset}
+		p.balances[asset] = b
+	}
+	return b
+}
+
+// balanceList returns copies of all balances sorted by asset.
+func (p *paperClient) balanceList() []order.Balance {
+	list := make([]order.Balance, 0, len(p.balances))
+	for _, b := range p.balances {
+		list = append(list, *b)
+	}
+	slices.SortFunc(list, func(a, b order.Balance) int {
+		return strings.Compare(a.Asset, b.Asset)
+	})
+	return list
+}
+
+// paperMatch returns the average price and quantity that an order for qty on
+// side would take from book. A non-zero limit restricts matching to levels at
+// or better than the limit price.
+func paperMatch(book *market.OrderBook, side market.Side, qty, limit udecimal.Decimal) (avg, filled udecimal.Decimal) {
+	view := market.OrderBook{Symbol: book.Symbol, Bids: book.Bids, Asks: book.Asks}
+	if !limit.IsZero() {
+		if side == market.SideBuy {
+			view.Asks = levelsWithin(book.Asks, func(price udecimal.Decimal) bool { return price.LessThanOrEqual(limit) })
+		} else {
+			view.Bids = levelsWithin(book.Bids, func(price udecimal.Decimal) bool { return price.GreaterThanOrEqual(limit) })
+		}
+	}
+	avg, filled, err := view.MarketImpact(side, qty)
+	if err != nil {
+		return udecimal.Decimal{}, udecimal.Decimal{}
+	}
+	return avg, filled
+}
+
+// levelsWithin returns the leading levels whose price satisfies ok.
+func levelsWithin(levels []market.OrderBookEntry, ok func(udecimal.Decimal) bool) []market.OrderBookEntry {
+	for i, lvl := range levels {
+		if !ok(lvl.Price) {
+			return levels[:i]
+		}
+	}
+	return levels
+}
+
+// cloneOrder returns a copy of o that callers may keep.
+func cloneOrder(o *order.Order) *order.Order {
+	c := *o
+	c.Fills = slices.Clone(o.Fills)
+	return &c
+}
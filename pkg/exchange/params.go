@@ -0,0 +1,252 @@
+package exchange
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+// OrderParams returns the provider-native parameters for placing req: native
+// field names, mapped type, side and time-in-force strings, and, when info is
+// non-nil, prices and quantities rounded down to the symbol's tick and step
+// size (see market.SymbolInfo.RoundPrice and RoundQty) and formatted at its
+// precision. A quantity that rounds to zero is rejected, as are order types
+// and options the provider does not offer on market m.
+// Authentication parameters (timestamp, signature) and, for Bybit, the
+// product category are not included.
+//
+// It lives here rather than on order.Request because the order package cannot
+// depend on exchange. Useful for logging exactly what is sent, testing
+// normalization, or building requests the SDK does not model yet.
+func OrderParams(p Provider, m MarketType, req *order.Request, info *market.SymbolInfo) (url.Values, error) {
+	if !m.IsValid() {
+		return nil, errors.NewValidationError("market_type", fmt.Sprintf("invalid market type: %s", m))
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if info != nil && !info.RoundQty(req.Quantity).IsPos() {
+		return nil, errors.NewValidationError("quantity", fmt.Sprintf("%s rounds to zero at step size %s", req.Quantity, info.StepSize))
+	}
+	price := func(d udecimal.Decimal) string {
+		if info != nil {
+			return info.FormatPrice(info.RoundPrice(d))
+		}
+		return d.String()
+	}
+	qty := func(d udecimal.Decimal) string {
+		if info != nil {
+			return info.FormatQty(info.RoundQty(d))
+		}
+		return d.String()
+	}
+
+	switch p {
+	case ProviderBinance:
+		return binanceOrderParams(m, req, price, qty)
+	case ProviderBybit:
+		return bybitOrderParams(m, req, price, qty)
+	default:
+		return nil, errors.NewValidationError("provider", fmt.Sprintf("invalid provider: %s", p))
+	}
+}
+
+// OrderQueryParams returns the provider-native parameters for looking up an
+// order of symbol by its exchange orderID or, if that is empty, by clientID:
+// orderId or origClientOrderId on Binance and orderId or orderLinkId on Bybit.
+func OrderQueryParams(p Provider, symbol market.Symbol, orderID, clientID string) (url.Values, error) {
+	if orderID == "" && clientID == "" {
+		return nil, errors.NewValidationError("order_id", "order ID or client ID is required")
+	}
+	v := url.Values{}
+	v.Set("symbol", string(symbol))
+	switch p {
+	case ProviderBinance:
+		if orderID != "" {
+			v.Set("orderId", orderID)
+		} else {
+			v.Set("origClientOrderId", clientID)
+		}
+	case ProviderBybit:
+		if orderID != "" {
+			v.Set("orderId", orderID)
+		} else {
+			v.Set("orderLinkId", clientID)
+		}
+	default:
+		return nil, errors.NewValidationError("provider", fmt.Sprintf("invalid provider: %s", p))
+	}
+	return v, nil
+}
+
+// binanceSpotTypes and binanceFuturesTypes name order types on the Binance
+// spot and USDⓈ-M futures APIs.
+var (
+	binanceSpotTypes = map[order.Type]string{
+		order.TypeLimit:           "LIMIT",
+		order.TypeMarket:          "MARKET",
+		order.TypeStopLoss:        "STOP_LOSS",
+		order.TypeStopLossLimit:   "STOP_LOSS_LIMIT",
+		order.TypeTakeProfit:      "TAKE_PROFIT",
+		order.TypeTakeProfitLimit: "TAKE_PROFIT_LIMIT",
+	}
+	binanceFuturesTypes = map[order.Type]string{
+		order.TypeLimit:           "LIMIT",
+		order.TypeMarket:          "MARKET",
+		order.TypeStopLoss:        "STOP_MARKET",
+		order.TypeStopLossLimit:   "STOP",
+		order.TypeTakeProfit:      "TAKE_PROFIT_MARKET",
+		order.TypeTakeProfitLimit: "TAKE_PROFIT",
+		order.TypeTrailingStop:    "TRAILING_STOP_MARKET",
+	}
+)
+
+// binanceOrderParams maps req to Binance order parameters for market m. Spot
+// takes LIMIT_MAKER for post-only and iceberg quantities; futures takes GTD,
+// trigger working types, trailing stops and reduce-only. Options of the other
+// market are rejected.
+func binanceOrderParams(m MarketType, req *order.Request, price, qty func(udecimal.Decimal) string) (url.Values, error) {
+	spot := m == MarketSpot
+	types := binanceFuturesTypes
+	if spot {
+		types = binanceSpotTypes
+	}
+	typ, ok := types[req.Type]
+	switch {
+	case !ok:
+		return nil, errors.NewValidationError("type", fmt.Sprintf("binance %s has no %s orders", m, req.Type))
+	case spot && req.TimeInForce == order.GTD:
+		return nil, errors.NewValidationError("time_in_force", "binance spot has no GTD orders")
+	case spot && req.TimeInForce == order.GTX && req.Type != order.TypeLimit:
+		return nil, errors.NewValidationError("time_in_force", "binance spot post-only orders must be limit orders")
+	case spot && req.WorkingType != order.WorkingTypeContract:
+		return nil, errors.NewValidationError("working_type", "binance spot triggers on the last price only")
+	case spot && req.ReduceOnly:
+		return nil, errors.NewValidationError("reduce_only", "binance spot orders cannot be reduce-only")
+	case !spot && !req.IcebergQty.IsZero():
+		return nil, errors.NewValidationError("iceberg_qty", "binance futures has no iceberg orders")
+	}
+	if spot && req.Type == order.TypeLimit && req.TimeInForce == order.GTX {
+		typ = "LIMIT_MAKER"
+	}
+
+	v := url.Values{}
+	v.Set("symbol", req.Symbol.String())
+	v.Set("side", map[market.Side]string{market.SideBuy: "BUY", market.SideSell: "SELL"}[req.Side])
+	v.Set("type", typ)
+	v.Set("quantity", qty(req.Quantity))
+	if req.Type.IsLimit() {
+		v.Set("price", price(req.Price))
+		if typ != "LIMIT_MAKER" {
+			v.Set("timeInForce", req.TimeInForce.String())
+		}
+		if req.TimeInForce == order.GTD {
+			v.Set("goodTillDate", strconv.FormatInt(req.GoodTillDate.UnixMilli(), 10))
+		}
+	}
+	switch {
+	case req.Type == order.TypeTrailingStop:
+		v.Set("callbackRate", req.CallbackRate.String())
+		if !req.StopPrice.IsZero() {
+			v.Set("activationPrice", price(req.StopPrice))
+		}
+	case req.Type.IsTrigger():
+		v.Set("stopPrice", price(req.StopPrice))
+	}
+	if !spot && req.Type.IsTrigger() {
+		switch req.WorkingType {
+		case order.WorkingTypeContract:
+		case order.WorkingTypeMark:
+			v.Set("workingType", "MARK_PRICE")
+		case order.WorkingTypeIndex:
+			return nil, fmt.Errorf("%w: binance index price triggers", errors.ErrNotSupported)
+		default:
+			return nil, errors.NewValidationError("working_type", fmt.Sprintf("unknown working type: %d", req.WorkingType))
+		}
+	}
+	if !req.IcebergQty.IsZero() {
+		v.Set("icebergQty", qty(req.IcebergQty))
+	}
+	if req.ClientID != "" {
+		v.Set("newClientOrderId", req.ClientID)
+	}
+	if req.ReduceOnly {
+		v.Set("reduceOnly", "true")
+	}
+	return v, nil
+}
+
+// bybitOrderParams maps req to Bybit v5 order parameters for market m. Spot
+// conditional orders are placed with orderFilter=StopOrder and trigger on the
+// last price; reduce-only is for futures (the linear category) only.
+func bybitOrderParams(m MarketType, req *order.Request, price, qty func(udecimal.Decimal) string) (url.Values, error) {
+	spot := m == MarketSpot
+	switch {
+	case spot && req.WorkingType != order.WorkingTypeContract:
+		return nil, errors.NewValidationError("working_type", "bybit spot triggers on the last price only")
+	case spot && req.ReduceOnly:
+		return nil, errors.NewValidationError("reduce_only", "bybit spot orders cannot be reduce-only")
+	}
+	if req.Type == order.TypeTrailingStop {
+		return nil, fmt.Errorf("%w: bybit trailing stops are set on the position", errors.ErrNotSupported)
+	}
+	if !req.IcebergQty.IsZero() {
+		return nil, fmt.Errorf("%w: bybit iceberg orders", errors.ErrNotSupported)
+	}
+	if req.TimeInForce == order.GTD {
+		return nil, fmt.Errorf("%w: bybit GTD orders", errors.ErrNotSupported)
+	}
+
+	v := url.Values{}
+	v.Set("symbol", req.Symbol.String())
+	v.Set("side", map[market.Side]string{market.SideBuy: "Buy", market.SideSell: "Sell"}[req.Side])
+	v.Set("orderType", "Market")
+	if req.Type.IsLimit() {
+		v.Set("orderType", "Limit")
+		v.Set("price", price(req.Price))
+		tif := req.TimeInForce.String()
+		if req.TimeInForce == order.GTX {
+			tif = "PostOnly"
+		}
+		v.Set("timeInForce", tif)
+	}
+	v.Set("qty", qty(req.Quantity))
+	switch {
+	case spot && req.Type.IsTrigger():
+		v.Set("triggerPrice", price(req.StopPrice))
+		v.Set("orderFilter", "StopOrder")
+	case req.Type.IsTrigger():
+		v.Set("triggerPrice", price(req.StopPrice))
+		// 1 triggers when the price rises to triggerPrice, 2 when it falls.
+		// Stops trigger against the order side, take-profits with it.
+		rises := req.Side == market.SideBuy
+		if req.Type == order.TypeTakeProfit || req.Type == order.TypeTakeProfitLimit {
+			rises = !rises
+		}
+		v.Set("triggerDirection", "2")
+		if rises {
+			v.Set("triggerDirection", "1")
+		}
+		triggerBy, ok := map[order.WorkingType]string{
+			order.WorkingTypeContract: "LastPrice",
+			order.WorkingTypeMark:     "MarkPrice",
+			order.WorkingTypeIndex:    "IndexPrice",
+		}[req.WorkingType]
+		if !ok {
+			return nil, errors.NewValidationError("working_type", fmt.Sprintf("unknown working type: %d", req.WorkingType))
+		}
+		v.Set("triggerBy", triggerBy)
+	}
+	if req.ClientID != "" {
+		v.Set("orderLinkId", req.ClientID)
+	}
+	if req.ReduceOnly {
+		v.Set("reduceOnly", "true")
+	}
+	return v, nil
+}
@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	stderrors "errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+func TestOrderParams(t *testing.T) {
+	qty, price, stop := udecimal.MustParse("0.5"), udecimal.MustParse("100"), udecimal.MustParse("90")
+	limit := func(tif order.TimeInForce) *order.Request {
+		return &order.Request{Symbol: "BTCUSDT", Side: market.SideBuy, Type: order.TypeLimit, Quantity: qty, Price: price, TimeInForce: tif}
+	}
+	stopLimit := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeStopLossLimit, Quantity: qty, Price: price, StopPrice: stop}
+	stopMarkPrice := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeStopLoss, Quantity: qty, StopPrice: stop, WorkingType: order.WorkingTypeMark}
+	trailing := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeTrailingStop, Quantity: qty, StopPrice: price, CallbackRate: udecimal.MustParse("1.5")}
+	gtd := limit(order.GTD)
+	gtd.GoodTillDate = time.UnixMilli(1700000000000)
+	reduceOnly := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeMarket, Quantity: qty, ReduceOnly: true}
+	iceberg := limit(order.GTC)
+	iceberg.IcebergQty = udecimal.MustParse("0.1")
+
+	tests := []struct {
+		name   string
+		p      Provider
+		m      MarketType
+		req    *order.Request
+		want   map[string]string // Parameters to check; "" means must be absent
+		reject string            // Field of the expected validation error
+	}{
+		{
+			name: "binance spot post-only",
+			p:    ProviderBinance, m: MarketSpot, req: limit(order.GTX),
+			want: map[string]string{"type": "LIMIT_MAKER", "price": "100", "timeInForce": ""},
+		},
+		{
+			name: "binance spot stop limit",
+			p:    ProviderBinance, m: MarketSpot, req: stopLimit,
+			want: map[string]string{"type": "STOP_LOSS_LIMIT", "stopPrice": "90", "timeInForce": "GTC", "workingType": ""},
+		},
+		{
+			name: "binance spot iceberg",
+			p:    ProviderBinance, m: MarketSpot, req: iceberg,
+			want: map[string]string{"type": "LIMIT", "icebergQty": "0.1"},
+		},
+		{name: "binance spot GTD", p: ProviderBinance, m: MarketSpot, req: gtd, reject: "time_in_force"},
+		{name: "binance spot reduce-only", p: ProviderBinance, m: MarketSpot, req: reduceOnly, reject: "reduce_only"},
+		{name: "binance spot mark price", p: ProviderBinance, m: MarketSpot, req: stopMarkPrice, reject: "working_type"},
+		{name: "binance spot trailing stop", p: ProviderBinance, m: MarketSpot, req: trailing, reject: "type"},
+		{
+			name: "binance futures post-only",
+			p:    ProviderBinance, m: MarketFutures, req: limit(order.GTX),
+			want: map[string]string{"type": "LIMIT", "timeInForce": "GTX"},
+		},
+		{
+			name: "binance futures GTD",
+			p:    ProviderBinance, m: MarketFutures, req: gtd,
+			want: map[string]string{"timeInForce": "GTD", "goodTillDate": "1700000000000"},
+		},
+		{
+			name: "binance futures stop limit",
+			p:    ProviderBinance, m: MarketFutures, req: stopLimit,
+			want: map[string]string{"type": "STOP", "stopPrice": "90"},
+		},
+		{
+			name: "binance futures stop on mark price",
+			p:    ProviderBinance, m: MarketFutures, req: stopMarkPrice,
+			want: map[string]string{"type": "STOP_MARKET", "stopPrice": "90", "workingType": "MARK_PRICE"},
+		},
+		{
+			name: "binance futures trailing stop",
+			p:    ProviderBinance, m: MarketFutures, req: trailing,
+			want: map[string]string{"type": "TRAILING_STOP_MARKET", "callbackRate": "1.5", "activationPrice": "100", "stopPrice": ""},
+		},
+		{
+			name: "binance futures reduce-only",
+			p:    ProviderBinance, m: MarketFutures, req: reduceOnly,
+			want: map[string]string{"type": "MARKET", "reduceOnly": "true"},
+		},
+		{name: "binance futures iceberg", p: ProviderBinance, m: MarketFutures, req: iceberg, reject: "iceberg_qty"},
+		{
+			name: "bybit spot stop limit",
+			p:    ProviderBybit, m: MarketSpot, req: stopLimit,
+			want: map[string]string{"orderType": "Limit", "triggerPrice": "90", "orderFilter": "StopOrder", "triggerDirection": "", "triggerBy": ""},
+		},
+		{name: "bybit spot reduce-only", p: ProviderBybit, m: MarketSpot, req: reduceOnly, reject: "reduce_only"},
+		{name: "bybit spot mark price", p: ProviderBybit, m: MarketSpot, req: stopMarkPrice, reject: "working_type"},
+		{
+			name: "bybit futures stop on mark price",
+			p:    ProviderBybit, m: MarketFutures, req: stopMarkPrice,
+			want: map[string]string{"orderType": "Market", "triggerPrice": "90", "triggerDirection": "2", "triggerBy": "MarkPrice", "orderFilter": ""},
+		},
+		{name: "invalid market type", p: ProviderBinance, m: "options", req: limit(order.GTC), reject: "market_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := OrderParams(tt.p, tt.m, tt.req, nil)
+			if tt.reject != "" {
+				var valErr *errors.ValidationError
+				if !stderrors.As(err, &valErr) || valErr.Field != tt.reject {
+					t.Fatalf("err = %v, want a %s validation error", err, tt.reject)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OrderParams: %v", err)
+			}
+			checkParams(t, v, tt.want)
+		})
+	}
+}
+
+func checkParams(t *testing.T, v url.Values, want map[string]string) {
+	t.Helper()
+	for k, w := range want {
+		if w == "" {
+			if v.Has(k) {
+				t.Errorf("%s = %q, want absent", k, v.Get(k))
+			}
+		} else if got := v.Get(k); got != w {
+			t.Errorf("%s = %q, want %q", k, got, w)
+		}
+	}
+}
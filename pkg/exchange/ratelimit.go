@@ -0,0 +1,29 @@
+package exchange
+
+import (
+	"time"
+)
+
+// RateLimitStatus is the request-weight budget reported by the exchange in
+// the headers of the latest REST response.
+type RateLimitStatus struct {
+	UsedWeight int       // Weight consumed in the current window
+	Limit      int       // Weight allowed per window; 0 if unknown
+	ResetAt    time.Time // When the current window ends; zero if unknown
+}
+
+// Usage returns UsedWeight as a fraction of Limit, or 0 if Limit is unknown.
+func (s RateLimitStatus) Usage() float64 {
+	if s.Limit <= 0 {
+		return 0
+	}
+	return float64(s.UsedWeight) / float64(s.Limit)
+}
+
+// Remaining returns the weight left in the current window, or -1 if Limit is unknown.
+func (s RateLimitStatus) Remaining() int {
+	if s.Limit <= 0 {
+		return -1
+	}
+	return max(s.Limit-s.UsedWeight, 0)
+}
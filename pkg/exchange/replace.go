@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/order"
+)
+
+// CancelThenPlace implements CancelReplace for providers without an atomic
+// cancel-replace endpoint: it cancels the order and, only if that succeeds,
+// places the replacement. Between the two calls neither order is working.
+//
+// If the order is no longer active (it filled or was already cancelled), the
+// replacement is not placed and the error wraps errors.ErrOrderNotActive, since
+// placing it could double the intended exposure. The new order's
+// ReplacesOrderID is the cancelled OrderID, or its ClientID if it was
+// cancelled by client ID.
+func CancelThenPlace(ctx context.Context, c Client, cancel *order.CancelRequest, place *order.Request) (*order.Order, error) {
+	if err := cancel.Validate(); err != nil {
+		return nil, err
+	}
+	if err := place.Validate(); err != nil {
+		return nil, err
+	}
+	if cancel.Symbol != place.Symbol {
+		return nil, errors.NewValidationError("symbol", "cancel and replacement must be for the same symbol")
+	}
+
+	if err := c.CancelOrder(ctx, cancel); err != nil {
+		return nil, fmt.Errorf("cancel: %w", err)
+	}
+	o, err := c.PlaceOrder(ctx, place)
+	if err != nil {
+		return nil, fmt.Errorf("place replacement: %w", err)
+	}
+	o.ReplacesOrderID = cancel.OrderID
+	if o.ReplacesOrderID == "" {
+		o.ReplacesOrderID = cancel.ClientID
+	}
+	return o, nil
+}
@@ -0,0 +1,32 @@
+package exchange
+
+import (
+	"runtime/debug"
+)
+
+// modulePath is the module path of the SDK, used to find its version.
+const modulePath = "github.com/pwnholic/clara"
+
+// DefaultUserAgent is the User-Agent sent when none is set with WithUserAgent,
+// e.g. "clara/v1.2.0 (+https://github.com/pwnholic/clara)".
+var DefaultUserAgent = "clara/" + moduleVersion() + " (+https://" + modulePath + ")"
+
+// moduleVersion returns the SDK version recorded in the build info, or "dev"
+// when built from a checkout of the SDK itself.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			if dep.Version != "" && dep.Version != "(devel)" {
+				return dep.Version
+			}
+		}
+	}
+	return "dev"
+}
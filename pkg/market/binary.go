@@ -0,0 +1,137 @@
+package market
+
+import (
+	"fmt"
+
+	"github.com/pwnholic/clara/internal/codec"
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// Binary layout versions.
+const (
+	klineBinaryVersion     = 1
+	orderBookBinaryVersion = 1
+)
+
+// minEntrySize is the smallest encoded OrderBookEntry: two 11-byte decimals.
+const minEntrySize = 22
+
+// MarshalBinary implements encoding.BinaryMarshaler with a compact format
+// for storage. JSON remains the wire format.
+func (k Kline) MarshalBinary() ([]byte, error) {
+	e := codec.NewEncoder(128)
+	e.Version(klineBinaryVersion)
+	e.String(string(k.Symbol))
+	e.String(string(k.Interval))
+	e.Time(k.OpenTime)
+	e.Time(k.CloseTime)
+	e.Decimal(k.Open)
+	e.Decimal(k.High)
+	e.Decimal(k.Low)
+	e.Decimal(k.Close)
+	e.Decimal(k.Volume)
+	e.Decimal(k.QuoteVolume)
+	e.Uint(k.TradeCount)
+	e.Bool(k.IsClosed)
+	return e.Bytes()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (k *Kline) UnmarshalBinary(data []byte) error {
+	d := codec.NewDecoder(data)
+	d.Version(klineBinaryVersion)
+	v := Kline{
+		Symbol:      Symbol(d.String()),
+		Interval:    KlineInterval(d.String()),
+		OpenTime:    d.Time(),
+		CloseTime:   d.Time(),
+		Open:        d.Decimal(),
+		High:        d.Decimal(),
+		Low:         d.Decimal(),
+		Close:       d.Decimal(),
+		Volume:      d.Decimal(),
+		QuoteVolume: d.Decimal(),
+		TradeCount:  d.Uint(),
+		IsClosed:    d.Bool(),
+	}
+	if err := d.Err(); err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with a compact format
+// for storage. JSON remains the wire format.
+func (ob OrderBook) MarshalBinary() ([]byte, error) {
+	e := codec.NewEncoder(32 + (len(ob.Bids)+len(ob.Asks))*minEntrySize)
+	e.Version(orderBookBinaryVersion)
+	e.String(string(ob.Symbol))
+	encodeEntries(e, ob.Bids)
+	encodeEntries(e, ob.Asks)
+	e.Time(ob.Timestamp)
+	e.Uint(ob.Sequence)
+	return e.Bytes()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (ob *OrderBook) UnmarshalBinary(data []byte) error {
+	d := codec.NewDecoder(data)
+	d.Version(orderBookBinaryVersion)
+	v := OrderBook{
+		Symbol:    Symbol(d.String()),
+		Bids:      decodeEntries(d),
+		Asks:      decodeEntries(d),
+		Timestamp: d.Time(),
+		Sequence:  d.Uint(),
+	}
+	if err := d.Err(); err != nil {
+		return err
+	}
+	*ob = v
+	return nil
+}
+
+// RestoreFrom loads a book saved with MarshalBinary, for resuming after a
+// restart without a cold snapshot. minSequence is the first sequence of the
+// oldest diff still available (e.g. the first one buffered from the live
+// stream): the saved book is only loaded if those diffs continue it, that is
+// if its Sequence+1 >= minSequence. Otherwise, or if it carries no sequence,
+// an error wrapping errors.ErrStaleSnapshot is returned and a fresh snapshot
+// must be fetched. The book is also validated (see Validate). On any error
+// ob is left unchanged.
+func (ob *OrderBook) RestoreFrom(data []byte, minSequence uint64) error {
+	var saved OrderBook
+	if err := saved.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("decode saved book: %w", err)
+	}
+	if saved.Sequence == 0 || saved.Sequence+1 < minSequence {
+		return fmt.Errorf("%w: %s saved at sequence %d, diffs start at %d",
+			errors.ErrStaleSnapshot, saved.Symbol, saved.Sequence, minSequence)
+	}
+	if err := saved.Validate(); err != nil {
+		return err
+	}
+	*ob = saved
+	return nil
+}
+
+func encodeEntries(e *codec.Encoder, entries []OrderBookEntry) {
+	e.Uint(uint64(len(entries)))
+	for _, entry := range entries {
+		e.Decimal(entry.Price)
+		e.Decimal(entry.Qty)
+	}
+}
+
+func decodeEntries(d *codec.Decoder) []OrderBookEntry {
+	n := d.Count(minEntrySize)
+	if n == 0 {
+		return nil
+	}
+	entries := make([]OrderBookEntry, n)
+	for i := range entries {
+		entries[i] = OrderBookEntry{Price: d.Decimal(), Qty: d.Decimal()}
+	}
+	return entries
+}
@@ -0,0 +1,294 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// maxPendingDiffs bounds the diffs buffered while a snapshot is fetched.
+// The oldest are dropped first; if they turn out to be needed the gap is
+// detected and another snapshot is fetched.
+const maxPendingDiffs = 10000
+
+// BookSource provides the snapshot and diff feeds a BookManager synchronizes.
+// exchange.Client satisfies it.
+type BookSource interface {
+	GetOrderBook(ctx context.Context, symbol Symbol, depth int) (*OrderBook, error)
+	OrderBookDiffStream(symbol Symbol, opts ...stream.Option) stream.Stream[OrderBookDiff]
+}
+
+// BookManager maintains a local order book from a REST snapshot and a diff
+// stream, and emits a copy of the book after every update.
+//
+// Diffs are buffered while the snapshot is fetched; those already covered by
+// the snapshot sequence are discarded and the rest applied in order. A sequence
+// gap or checksum mismatch discards the book and triggers a fresh snapshot,
+// which is reported on the error channel. So does a reconnect of the diff
+// stream (a new StreamInfo.ConnectedSince), since sequence numbers may reset
+// or jump across connections; diffs buffered before it are discarded.
+// Emitted books are trimmed to depth levels per side. See WithBookCoalescing
+// for consumers that may fall behind.
+type BookManager struct {
+	*stream.BaseStream[OrderBook]
+
+	// OnResync, if set before Subscribe, is called with the reason whenever
+	// the book is discarded and a fresh snapshot fetched. It runs on the
+	// manager's goroutine and must not block.
+	OnResync func(reason string)
+
+	source   BookSource
+	symbol   Symbol
+	depth    int
+	checksum func(OrderBook) uint32
+	crossed  func(OrderBook) bool
+	coalesce bool
+	diffs    stream.Stream[OrderBookDiff]
+}
+
+// BookManagerOption configures a BookManager.
+type BookManagerOption func(*BookManager)
+
+// WithBookChecksum verifies the local book against the Checksum carried by
+// diffs, using fn to compute the exchange's checksum of a book. Diffs without
+// a checksum are not verified.
+func WithBookChecksum(fn func(OrderBook) uint32) BookManagerOption {
+	return func(m *BookManager) {
+		m.checksum = fn
+	}
+}
+
+// WithCrossedBookCheck checks the book after every diff for a crossed or
+// locked market (see OrderBook.IsCrossed and IsLocked) and passes such a book
+// to fn. If fn returns true the book is treated as corrupt and resynced;
+// otherwise it is emitted as usual. A nil fn always resyncs.
+func WithCrossedBookCheck(fn func(OrderBook) bool) BookManagerOption {
+	return func(m *BookManager) {
+		m.crossed = fn
+		if fn == nil {
+			m.crossed = func(OrderBook) bool { return true }
+		}
+	}
+}
+
+// WithBookCoalescing keeps at most one book waiting for a slow consumer. A
+// book the consumer has not read yet is replaced by the next one instead of
+// the newer one being dropped, so a reader that falls behind skips the
+// intermediate states but always reads the fully applied book at the latest
+// sequence. Without it, books are dropped once the buffer is full.
+func WithBookCoalescing() BookManagerOption {
+	return func(m *BookManager) {
+		m.coalesce = true
+	}
+}
+
+// NewBookManager returns a BookManager for symbol. depth is the number of
+// levels per side to fetch and keep (0 = full depth).
+func NewBookManager(source BookSource, symbol Symbol, depth int, opts ...BookManagerOption) *BookManager {
+	m := &BookManager{
+		source: source,
+		symbol: symbol,
+		depth:  depth,
+		diffs:  source.OrderBookDiffStream(symbol),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	cfg := stream.DefaultConfig()
+	if m.coalesce {
+		cfg.BufferSize = 1
+	}
+	m.BaseStream = stream.NewBaseStream[OrderBook](cfg)
+	m.SetLabels(stream.Labels{Stream: "orderbook", Symbol: string(symbol)})
+	return m
+}
+
+// Subscribe subscribes the diff stream and starts synchronizing the book.
+func (m *BookManager) Subscribe(ctx context.Context) (<-chan OrderBook, error) {
+	diffs, err := m.diffs.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := m.DataChannel()
+	if err := m.Start(ctx, func(ctx context.Context) error { return m.run(ctx, diffs) }); err != nil {
+		_ = m.diffs.Unsubscribe(ctx)
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe stops the manager and unsubscribes the diff stream.
+func (m *BookManager) Unsubscribe(ctx context.Context) error {
+	if err := m.Stop(); err != nil {
+		return err
+	}
+	return m.diffs.Unsubscribe(ctx)
+}
+
+// snapshotResult is the outcome of an asynchronous snapshot fetch.
+type snapshotResult struct {
+	book *OrderBook
+	err  error
+}
+
+// run synchronizes the book until ctx is cancelled or the diff stream closes.
+func (m *BookManager) run(ctx context.Context, diffs <-chan OrderBookDiff) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Go(func() { m.forwardErrors(ctx) })
+	defer wg.Wait()
+	defer cancel()
+
+	var (
+		book      *OrderBook
+		pending   []OrderBookDiff
+		connected = m.diffs.StreamInfo().ConnectedSince
+	)
+	snap := m.fetchSnapshot(ctx, 0)
+
+	// resync drops the book and fetches a new snapshot. Diffs from keep on
+	// stay buffered since they may be newer than the next snapshot.
+	resync := func(err error, keep []OrderBookDiff) {
+		m.EmitError(fmt.Errorf("resync %s: %w", m.symbol, err))
+		if m.OnResync != nil {
+			m.OnResync(err.Error())
+		}
+		book = nil
+		pending = append([]OrderBookDiff(nil), keep...)
+		snap = m.fetchSnapshot(ctx, 0)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case res := <-snap:
+			if res.err != nil {
+				m.EmitError(fmt.Errorf("fetch %s snapshot: %w", m.symbol, res.err))
+				snap = m.fetchSnapshot(ctx, m.Config().ReconnectBaseDelay)
+				continue
+			}
+			snap = nil
+			book = res.book
+			book.Trim(m.depth)
+			buffered := pending
+			pending = nil
+			for i, d := range buffered {
+				if err := m.apply(book, d); err != nil {
+					resync(err, buffered[i:])
+					break
+				}
+			}
+			if book != nil {
+				m.emit(book)
+			}
+
+		case d, ok := <-diffs:
+			if !ok {
+				return fmt.Errorf("%w: %s diff stream closed", errors.ErrDisconnected, m.symbol)
+			}
+			if since := m.diffs.StreamInfo().ConnectedSince; !since.Equal(connected) && !since.IsZero() {
+				first := connected.IsZero()
+				connected = since
+				if !first {
+					// d is from the new connection; everything before it is not.
+					resync(fmt.Errorf("diff stream reconnected at %s", since.Format(time.RFC3339)), []OrderBookDiff{d})
+					continue
+				}
+			}
+			if book == nil {
+				pending = append(pending, d)
+				if len(pending) > maxPendingDiffs {
+					pending = pending[len(pending)-maxPendingDiffs:]
+				}
+				continue
+			}
+			if err := m.apply(book, d); err != nil {
+				resync(err, []OrderBookDiff{d})
+				continue
+			}
+			m.emit(book)
+		}
+	}
+}
+
+// emit emits a copy of book. When coalescing, an unread book is replaced.
+func (m *BookManager) emit(book *OrderBook) {
+	c := book.Clone()
+	if m.Emit(c) || !m.coalesce {
+		return
+	}
+	// Only run emits, so once the stale book is taken the send succeeds
+	// unless the stream is closing.
+	select {
+	case <-m.DataChannel():
+	default:
+	}
+	m.Emit(c)
+}
+
+// apply applies d to book and verifies the checksum and, if enabled, that it
+// is not crossed. On an error the book is corrupt and must be discarded.
+func (m *BookManager) apply(book *OrderBook, d OrderBookDiff) error {
+	if err := book.ApplyDiffAndTrim(d, m.depth); err != nil {
+		return err
+	}
+	if m.checksum != nil && d.Checksum != 0 {
+		if got := m.checksum(*book); got != d.Checksum {
+			return fmt.Errorf("%w: book %d, exchange %d at sequence %d", errors.ErrChecksumMismatch, got, d.Checksum, book.Sequence)
+		}
+	}
+	if m.crossed != nil && (book.IsCrossed() || book.IsLocked()) && m.crossed(book.Clone()) {
+		return fmt.Errorf("%w: bid %s, ask %s at sequence %d", errors.ErrCrossedBook, book.Bids[0].Price, book.Asks[0].Price, book.Sequence)
+	}
+	return nil
+}
+
+// fetchSnapshot fetches a snapshot after delay in the background. The result
+// is delivered on the returned channel unless ctx is cancelled.
+func (m *BookManager) fetchSnapshot(ctx context.Context, delay time.Duration) <-chan snapshotResult {
+	ch := make(chan snapshotResult, 1)
+	go func() {
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+		}
+		book, err := m.source.GetOrderBook(ctx, m.symbol, m.depth)
+		switch {
+		case err == nil && book == nil:
+			err = fmt.Errorf("%w: empty %s snapshot", errors.ErrNotFound, m.symbol)
+		case err == nil:
+			book.Sort()
+			err = book.Validate()
+		}
+		ch <- snapshotResult{book: book, err: err}
+	}()
+	return ch
+}
+
+// forwardErrors relays diff stream errors until its error channel closes or
+// ctx is cancelled.
+func (m *BookManager) forwardErrors(ctx context.Context) {
+	errs := m.diffs.Errors()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			m.EmitError(err)
+		}
+	}
+}
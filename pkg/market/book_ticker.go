@@ -0,0 +1,47 @@
+package market
+
+import (
+	"time"
+
+	"github.com/quagmt/udecimal"
+)
+
+// BookTicker is the top of an order book, emitted whenever the best bid or
+// offer changes (Binance bookTicker, Bybit tickers). It is much lighter than
+// an OrderBook stream when only the touch is needed.
+type BookTicker struct {
+	Symbol    Symbol           `json:"symbol"`
+	BidPrice  udecimal.Decimal `json:"bid_price"`
+	BidQty    udecimal.Decimal `json:"bid_qty"`
+	AskPrice  udecimal.Decimal `json:"ask_price"`
+	AskQty    udecimal.Decimal `json:"ask_qty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (b BookTicker) ExchangeTime() time.Time {
+	return b.Timestamp
+}
+
+// Spread returns the bid-ask spread (ask - bid).
+func (b BookTicker) Spread() udecimal.Decimal {
+	return b.AskPrice.Sub(b.BidPrice)
+}
+
+// MidPrice returns the mid-price ((bid + ask) / 2).
+func (b BookTicker) MidPrice() (udecimal.Decimal, error) {
+	return b.BidPrice.Add(b.AskPrice).Div64(2)
+}
+
+// Ticker returns the touch as a Ticker with the last price and 24h
+// statistics left zero, for use with Ticker helpers such as SpreadBps.
+func (b BookTicker) Ticker() Ticker {
+	return Ticker{
+		Symbol:    b.Symbol,
+		BidPrice:  b.BidPrice,
+		BidQty:    b.BidQty,
+		AskPrice:  b.AskPrice,
+		AskQty:    b.AskQty,
+		Timestamp: b.Timestamp,
+	}
+}
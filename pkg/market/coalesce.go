@@ -0,0 +1,18 @@
+package market
+
+import (
+	"github.com/pwnholic/clara/pkg/stream"
+)
+
+// CoalesceDiffs wraps a diff stream for slow consumers. Diffs arriving between
+// reads are merged with OrderBookDiff.Merge into one diff whose application is
+// equivalent to applying each in order, so a consumer that applies what it
+// reads always lands on the current book at the latest sequence.
+// A sequence gap is never merged over: the diffs on either side of it are
+// delivered separately so ApplyDiff can detect the gap.
+func CoalesceDiffs(src stream.Stream[OrderBookDiff]) stream.Stream[OrderBookDiff] {
+	return stream.Coalesce(src, func(pending, next OrderBookDiff) (OrderBookDiff, bool) {
+		merged, err := pending.Merge(next)
+		return merged, err == nil
+	})
+}
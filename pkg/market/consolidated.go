@@ -0,0 +1,37 @@
+package market
+
+import (
+	"time"
+
+	"github.com/quagmt/udecimal"
+)
+
+// ConsolidatedTicker is the best bid and offer for a symbol across venues.
+// Venues are identified by provider name (exchange.Provider).
+type ConsolidatedTicker struct {
+	Symbol    Symbol           `json:"symbol"`
+	BidPrice  udecimal.Decimal `json:"bid_price"` // Highest bid across venues
+	BidQty    udecimal.Decimal `json:"bid_qty"`
+	BidVenue  string           `json:"bid_venue"`
+	AskPrice  udecimal.Decimal `json:"ask_price"` // Lowest ask across venues
+	AskQty    udecimal.Decimal `json:"ask_qty"`
+	AskVenue  string           `json:"ask_venue"`
+	Venues    []string         `json:"venues"` // Venues with a fresh ticker, sorted
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (t ConsolidatedTicker) ExchangeTime() time.Time {
+	return t.Timestamp
+}
+
+// Spread returns AskPrice - BidPrice, which is negative when crossed.
+func (t ConsolidatedTicker) Spread() udecimal.Decimal {
+	return t.AskPrice.Sub(t.BidPrice)
+}
+
+// IsCrossed returns true if one venue bids above another's ask, an
+// arbitrage opportunity before fees.
+func (t ConsolidatedTicker) IsCrossed() bool {
+	return t.BidVenue != t.AskVenue && t.BidPrice.GreaterThan(t.AskPrice)
+}
@@ -0,0 +1,98 @@
+package market
+
+import (
+	"fmt"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/quagmt/udecimal"
+)
+
+// CrossTicker synthesizes the ticker of a pair that is not quoted directly.
+//
+// With invert false the legs are multiplied: a is A/X and b is X/B (e.g.
+// ETHBTC × BTCUSDT = ETHUSDT). With invert true a is divided by b: a is A/Q
+// and b is B/Q (e.g. ETHUSDT ÷ BTCUSDT = ETHBTC).
+//
+// The spread is conservative: the synthetic bid is what selling through both
+// legs at their touch yields and the ask is what buying through both costs,
+// so it is never narrower than the legs combined. Bid and ask quantities are
+// in the synthetic base asset, limited by the thinner leg. The 24h statistics
+// are left zero and Timestamp is the older of the two.
+func CrossTicker(a, b Ticker, invert bool) (Ticker, error) {
+	if err := validateCrossLeg("a", a); err != nil {
+		return Ticker{}, err
+	}
+	if err := validateCrossLeg("b", b); err != nil {
+		return Ticker{}, err
+	}
+
+	t := Ticker{Timestamp: a.Timestamp}
+	if b.Timestamp.Before(t.Timestamp) {
+		t.Timestamp = b.Timestamp
+	}
+
+	var err error
+	if !invert {
+		if a.Symbol.Quote() == "" || a.Symbol.Quote() != b.Symbol.Base() {
+			return Ticker{}, fmt.Errorf("%w: %s and %s do not chain (quote of the first must be the base of the second)",
+				errors.ErrInvalidSymbol, a.Symbol, b.Symbol)
+		}
+		t.Symbol = Symbol(a.Symbol.Base() + b.Symbol.Quote())
+		t.LastPrice = a.LastPrice.Mul(b.LastPrice)
+		t.BidPrice = a.BidPrice.Mul(b.BidPrice)
+		t.AskPrice = a.AskPrice.Mul(b.AskPrice)
+
+		// Selling A yields X at a's bid, which is sold on b's bid; buying is the reverse.
+		if t.BidQty, err = crossQty(a.BidQty, b.BidQty, a.BidPrice); err != nil {
+			return Ticker{}, err
+		}
+		if t.AskQty, err = crossQty(a.AskQty, b.AskQty, a.AskPrice); err != nil {
+			return Ticker{}, err
+		}
+		return t, nil
+	}
+
+	if a.Symbol.Quote() == "" || a.Symbol.Quote() != b.Symbol.Quote() {
+		return Ticker{}, fmt.Errorf("%w: %s and %s do not chain (quote assets must match)",
+			errors.ErrInvalidSymbol, a.Symbol, b.Symbol)
+	}
+	t.Symbol = Symbol(a.Symbol.Base() + b.Symbol.Base())
+	if t.BidPrice, err = a.BidPrice.Div(b.AskPrice); err != nil {
+		return Ticker{}, err
+	}
+	if t.AskPrice, err = a.AskPrice.Div(b.BidPrice); err != nil {
+		return Ticker{}, err
+	}
+	if !b.LastPrice.IsZero() {
+		if t.LastPrice, err = a.LastPrice.Div(b.LastPrice); err != nil {
+			return Ticker{}, err
+		}
+	}
+
+	// Selling A yields Q at a's bid, which buys B at b's ask; buying is the reverse.
+	if t.BidQty, err = crossQty(a.BidQty, b.AskQty.Mul(b.AskPrice), a.BidPrice); err != nil {
+		return Ticker{}, err
+	}
+	if t.AskQty, err = crossQty(a.AskQty, b.BidQty.Mul(b.BidPrice), a.AskPrice); err != nil {
+		return Ticker{}, err
+	}
+	return t, nil
+}
+
+// crossQty returns the base quantity both legs can fill: qty on the first leg,
+// or other (in the first leg's quote asset) converted at price, whichever is less.
+func crossQty(qty, other, price udecimal.Decimal) (udecimal.Decimal, error) {
+	converted, err := other.Div(price)
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	return udecimal.Min(qty, converted), nil
+}
+
+// validateCrossLeg checks that a leg has a usable touch.
+func validateCrossLeg(field string, t Ticker) error {
+	if !t.BidPrice.IsPos() || !t.AskPrice.IsPos() {
+		return errors.NewValidationError(field, fmt.Sprintf("%s has no bid/ask", t.Symbol))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package market
+
+import (
+	"strconv"
+
+	"github.com/quagmt/udecimal"
+)
+
+// maxDecimalPrec is the most decimal places a udecimal.Decimal can hold.
+const maxDecimalPrec = 19
+
+// NormalizeDecimal truncates d to prec decimal places and stores it at exactly
+// that scale, so that values from venues quoting different trailing
+// precision (e.g. "42000.10" and "42000.1") are represented, encoded and
+// compared as struct values identically. prec is capped at 19; zero is
+// always stored at scale 0.
+func NormalizeDecimal(d udecimal.Decimal, prec uint8) udecimal.Decimal {
+	prec = min(prec, maxDecimalPrec)
+	d = d.Trunc(prec)
+	if pad := prec - d.PrecUint(); pad > 0 {
+		scale := uint64(1)
+		for range pad {
+			scale *= 10
+		}
+		d = d.Mul(udecimal.MustFromUint64(scale, pad))
+	}
+	return d
+}
+
+// SafeDiv returns a / b, or fallback if b is zero or the quotient overflows.
+// It suits hot paths where a ratio with nothing to divide by is simply
+// fallback, typically zero, rather than an error to handle.
+func SafeDiv(a, b, fallback udecimal.Decimal) udecimal.Decimal {
+	if b.IsZero() {
+		return fallback
+	}
+	q, err := a.Div(b)
+	if err != nil {
+		return fallback
+	}
+	return q
+}
+
+// FixedDecimal is a udecimal.Decimal that serializes with a fixed number of
+// decimal places, keeping trailing zeros (e.g. "42000.00" rather than "42000").
+// Values with more decimals than Prec are serialized unchanged, never rounded.
+type FixedDecimal struct {
+	Value udecimal.Decimal
+	Prec  uint8
+}
+
+// NewFixedDecimal creates a FixedDecimal with the given precision.
+func NewFixedDecimal(d udecimal.Decimal, prec uint8) FixedDecimal {
+	return FixedDecimal{Value: d, Prec: prec}
+}
+
+// String returns the fixed-precision string representation.
+func (d FixedDecimal) String() string {
+	return d.Value.StringFixed(d.Prec)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d FixedDecimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The precision is taken from the number of decimals in the text.
+func (d *FixedDecimal) UnmarshalText(text []byte) error {
+	v, err := udecimal.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	d.Value = v
+	d.Prec = v.PrecUint()
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The value is quoted, matching udecimal.Decimal.
+func (d FixedDecimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Both quoted and bare numbers are accepted.
+func (d *FixedDecimal) UnmarshalJSON(data []byte) error {
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
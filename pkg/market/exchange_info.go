@@ -0,0 +1,122 @@
+package market
+
+import (
+	"slices"
+	"strings"
+)
+
+// ExchangeInfoDiff is the difference between two exchange-info snapshots.
+// All slices are sorted by symbol.
+type ExchangeInfoDiff struct {
+	Added   []SymbolInfo   `json:"added"`   // Symbols only in the new snapshot (listings)
+	Removed []SymbolInfo   `json:"removed"` // Symbols only in the old snapshot (delistings)
+	Changed []SymbolChange `json:"changed"` // Symbols whose rules or status changed
+}
+
+// IsEmpty returns true if the snapshots are equivalent.
+func (d ExchangeInfoDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// SymbolChange describes how the rules of a single symbol changed.
+type SymbolChange struct {
+	Symbol Symbol     `json:"symbol"`
+	Old    SymbolInfo `json:"old"`
+	New    SymbolInfo `json:"new"`
+	Fields []string   `json:"fields"` // Changed fields by JSON name, e.g. "tick_size", "status"
+}
+
+// HasField returns true if the named field changed.
+func (c SymbolChange) HasField(field string) bool {
+	return slices.Contains(c.Fields, field)
+}
+
+// StatusChanged returns true if the trading status changed (e.g. TRADING -> HALTED).
+func (c SymbolChange) StatusChanged() bool {
+	return c.Old.Status != c.New.Status
+}
+
+// DiffExchangeInfo compares two exchange-info snapshots and reports listings,
+// delistings, and per-symbol rule or status changes. Decimal rules are compared
+// by value, so differently scaled but equal values are not reported.
+// It runs in O(n log n) over the combined symbol count.
+func DiffExchangeInfo(old, new []SymbolInfo) ExchangeInfoDiff {
+	oldBySymbol := make(map[Symbol]SymbolInfo, len(old))
+	for _, info := range old {
+		oldBySymbol[info.Symbol] = info
+	}
+
+	var diff ExchangeInfoDiff
+	seen := make(map[Symbol]struct{}, len(new))
+	for _, info := range new {
+		seen[info.Symbol] = struct{}{}
+		prev, ok := oldBySymbol[info.Symbol]
+		if !ok {
+			diff.Added = append(diff.Added, info)
+			continue
+		}
+		if fields := changedFields(prev, info); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, SymbolChange{
+				Symbol: info.Symbol,
+				Old:    prev,
+				New:    info,
+				Fields: fields,
+			})
+		}
+	}
+	for _, info := range old {
+		if _, ok := seen[info.Symbol]; !ok {
+			diff.Removed = append(diff.Removed, info)
+		}
+	}
+
+	bySymbol := func(a, b SymbolInfo) int { return strings.Compare(string(a.Symbol), string(b.Symbol)) }
+	slices.SortFunc(diff.Added, bySymbol)
+	slices.SortFunc(diff.Removed, bySymbol)
+	slices.SortFunc(diff.Changed, func(a, b SymbolChange) int {
+		return strings.Compare(string(a.Symbol), string(b.Symbol))
+	})
+	return diff
+}
+
+// changedFields returns the JSON names of the fields that differ between a and b.
+func changedFields(a, b SymbolInfo) []string {
+	var fields []string
+	if a.Status != b.Status {
+		fields = append(fields, "status")
+	}
+	if a.BaseAsset != b.BaseAsset {
+		fields = append(fields, "base_asset")
+	}
+	if a.QuoteAsset != b.QuoteAsset {
+		fields = append(fields, "quote_asset")
+	}
+	if a.PricePrecision != b.PricePrecision {
+		fields = append(fields, "price_precision")
+	}
+	if a.QuantityPrecision != b.QuantityPrecision {
+		fields = append(fields, "quantity_precision")
+	}
+	if !a.TickSize.Equal(b.TickSize) {
+		fields = append(fields, "tick_size")
+	}
+	if !a.StepSize.Equal(b.StepSize) {
+		fields = append(fields, "step_size")
+	}
+	if !a.MinQty.Equal(b.MinQty) {
+		fields = append(fields, "min_qty")
+	}
+	if !a.MaxQty.Equal(b.MaxQty) {
+		fields = append(fields, "max_qty")
+	}
+	if !a.MinNotional.Equal(b.MinNotional) {
+		fields = append(fields, "min_notional")
+	}
+	if !a.PriceMultiplierUp.Equal(b.PriceMultiplierUp) {
+		fields = append(fields, "price_multiplier_up")
+	}
+	if !a.PriceMultiplierDown.Equal(b.PriceMultiplierDown) {
+		fields = append(fields, "price_multiplier_down")
+	}
+	return fields
+}
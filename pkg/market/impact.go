@@ -0,0 +1,79 @@
+package market
+
+import (
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/quagmt/udecimal"
+)
+
+// MarketImpact estimates the execution of a market order for qty on side by
+// walking the book: buys consume asks from the best price up, sells consume
+// bids from the best price down. It returns the volume-weighted average price
+// and the quantity filled, which is less than qty when the book is too thin.
+func (ob OrderBook) MarketImpact(side Side, qty udecimal.Decimal) (avgPrice, filled udecimal.Decimal, err error) {
+	if !qty.IsPos() {
+		return udecimal.Decimal{}, udecimal.Decimal{}, errors.NewValidationError("qty", "must be positive")
+	}
+	levels := ob.Asks
+	if side == SideSell {
+		levels = ob.Bids
+	}
+
+	var cost udecimal.Decimal
+	remaining := qty
+	for _, lvl := range levels {
+		take := udecimal.Min(remaining, lvl.Qty)
+		cost = cost.Add(take.Mul(lvl.Price))
+		filled = filled.Add(take)
+		remaining = remaining.Sub(take)
+		if remaining.IsZero() {
+			break
+		}
+	}
+	if filled.IsZero() {
+		return udecimal.Decimal{}, udecimal.Decimal{}, errors.NewValidationError("orderbook", "no liquidity")
+	}
+	avgPrice, err = cost.Div(filled)
+	if err != nil {
+		return udecimal.Decimal{}, udecimal.Decimal{}, err
+	}
+	return avgPrice, filled, nil
+}
+
+// ImpactForNotional is MarketImpact sized in the quote asset: it walks the
+// book until notional (price * qty) has been spent on a buy or received on a
+// sell. It returns the volume-weighted average price and the base quantity
+// filled; when the book is too thin the whole side is consumed and less than
+// notional is traded. The partial level is not rounded to a step size.
+func (ob OrderBook) ImpactForNotional(side Side, notional udecimal.Decimal) (avgPrice, baseFilled udecimal.Decimal, err error) {
+	if !notional.IsPos() {
+		return udecimal.Decimal{}, udecimal.Decimal{}, errors.NewValidationError("notional", "must be positive")
+	}
+	levels := ob.Asks
+	if side == SideSell {
+		levels = ob.Bids
+	}
+
+	var spent udecimal.Decimal
+	for _, lvl := range levels {
+		value := lvl.Value()
+		if remaining := notional.Sub(spent); value.GreaterThanOrEqual(remaining) {
+			take, err := remaining.Div(lvl.Price)
+			if err != nil {
+				return udecimal.Decimal{}, udecimal.Decimal{}, err
+			}
+			baseFilled = baseFilled.Add(take)
+			spent = notional
+			break
+		}
+		baseFilled = baseFilled.Add(lvl.Qty)
+		spent = spent.Add(value)
+	}
+	if baseFilled.IsZero() {
+		return udecimal.Decimal{}, udecimal.Decimal{}, errors.NewValidationError("orderbook", "no liquidity")
+	}
+	avgPrice, err = spent.Div(baseFilled)
+	if err != nil {
+		return udecimal.Decimal{}, udecimal.Decimal{}, err
+	}
+	return avgPrice, baseFilled, nil
+}
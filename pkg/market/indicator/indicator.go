@@ -0,0 +1,106 @@
+// Package indicator provides technical indicators over kline series.
+// All math uses udecimal.Decimal, matching the precision of the market types.
+//
+// Every indicator returns a slice aligned to its input: element i corresponds
+// to klines[i]. Entries before the indicator has enough history (its warm-up
+// period) are zero, since udecimal has no NaN.
+package indicator
+
+import (
+	"fmt"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// SMA returns the simple moving average of close prices over period.
+// The first period-1 entries are zero.
+func SMA(klines []market.Kline, period int) ([]udecimal.Decimal, error) {
+	if err := validatePeriod(len(klines), period); err != nil {
+		return nil, err
+	}
+	return sma(closes(klines), period)
+}
+
+// EMA returns the exponential moving average of close prices over period,
+// with smoothing factor 2/(period+1). It is seeded with the SMA of the first
+// period closes, so the first period-1 entries are zero.
+func EMA(klines []market.Kline, period int) ([]udecimal.Decimal, error) {
+	if err := validatePeriod(len(klines), period); err != nil {
+		return nil, err
+	}
+	return ema(closes(klines), period)
+}
+
+// closes extracts the close prices of klines.
+func closes(klines []market.Kline) []udecimal.Decimal {
+	out := make([]udecimal.Decimal, len(klines))
+	for i, k := range klines {
+		out[i] = k.Close
+	}
+	return out
+}
+
+// validatePeriod checks that period is positive and n values cover it.
+func validatePeriod(n, period int) error {
+	return validateKlines(n, period, period)
+}
+
+// validateKlines checks that period is positive and there are at least need
+// of the n klines.
+func validateKlines(n, period, need int) error {
+	if period <= 0 {
+		return errors.NewValidationError("period", "must be positive")
+	}
+	if n < need {
+		return errors.NewValidationError("klines", fmt.Sprintf("need at least %d klines, got %d", need, n))
+	}
+	return nil
+}
+
+// sma computes a simple moving average over values using a running sum.
+func sma(values []udecimal.Decimal, period int) ([]udecimal.Decimal, error) {
+	out := make([]udecimal.Decimal, len(values))
+	var sum udecimal.Decimal
+	for i, v := range values {
+		sum = sum.Add(v)
+		if i >= period {
+			sum = sum.Sub(values[i-period])
+		}
+		if i < period-1 {
+			continue
+		}
+		avg, err := sum.Div64(uint64(period))
+		if err != nil {
+			return nil, fmt.Errorf("sma at %d: %w", i, err)
+		}
+		out[i] = avg
+	}
+	return out, nil
+}
+
+// ema computes an exponential moving average over values, seeded with the SMA
+// of the first period values.
+func ema(values []udecimal.Decimal, period int) ([]udecimal.Decimal, error) {
+	alpha, err := udecimal.MustFromInt64(2, 0).Div64(uint64(period + 1))
+	if err != nil {
+		return nil, fmt.Errorf("ema smoothing factor: %w", err)
+	}
+
+	out := make([]udecimal.Decimal, len(values))
+	var seed udecimal.Decimal
+	for _, v := range values[:period] {
+		seed = seed.Add(v)
+	}
+	prev, err := seed.Div64(uint64(period))
+	if err != nil {
+		return nil, fmt.Errorf("ema seed: %w", err)
+	}
+	out[period-1] = prev
+	for i := period; i < len(values); i++ {
+		prev = values[i].Sub(prev).Mul(alpha).Add(prev)
+		out[i] = prev
+	}
+	return out, nil
+}
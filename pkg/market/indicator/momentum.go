@@ -0,0 +1,136 @@
+package indicator
+
+import (
+	"fmt"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+var hundred = udecimal.MustFromInt64(100, 0)
+
+// RSI returns the relative strength index of close prices over period using
+// Wilder's smoothing: the first average gain/loss is the simple mean of the
+// first period changes, and each later average is (prev*(period-1)+cur)/period.
+//
+// It needs more than period klines; the first period entries are zero. When
+// there are no losses the RSI is 100, and 50 when price did not move at all.
+func RSI(klines []market.Kline, period int) ([]udecimal.Decimal, error) {
+	if err := validateKlines(len(klines), period, period+1); err != nil {
+		return nil, err
+	}
+
+	out := make([]udecimal.Decimal, len(klines))
+	var avgGain, avgLoss udecimal.Decimal
+	for i := 1; i < len(klines); i++ {
+		change := klines[i].Close.Sub(klines[i-1].Close)
+		var gain, loss udecimal.Decimal
+		if change.IsPos() {
+			gain = change
+		} else {
+			loss = change.Neg()
+		}
+
+		if i <= period {
+			avgGain = avgGain.Add(gain)
+			avgLoss = avgLoss.Add(loss)
+			if i < period {
+				continue
+			}
+			var err error
+			if avgGain, err = avgGain.Div64(uint64(period)); err != nil {
+				return nil, fmt.Errorf("rsi at %d: %w", i, err)
+			}
+			if avgLoss, err = avgLoss.Div64(uint64(period)); err != nil {
+				return nil, fmt.Errorf("rsi at %d: %w", i, err)
+			}
+		} else {
+			var err error
+			if avgGain, err = wilder(avgGain, gain, period); err != nil {
+				return nil, fmt.Errorf("rsi at %d: %w", i, err)
+			}
+			if avgLoss, err = wilder(avgLoss, loss, period); err != nil {
+				return nil, fmt.Errorf("rsi at %d: %w", i, err)
+			}
+		}
+
+		rsi, err := rsiValue(avgGain, avgLoss)
+		if err != nil {
+			return nil, fmt.Errorf("rsi at %d: %w", i, err)
+		}
+		out[i] = rsi
+	}
+	return out, nil
+}
+
+// MACD returns the moving average convergence/divergence of close prices:
+// the macd line EMA(fast) - EMA(slow), its signal line EMA(signal) of the macd
+// line, and the histogram macd - signal.
+//
+// fast must be less than slow, and there must be at least slow+signal-1
+// klines so the signal line has a value. The macd line is zero before index
+// slow-1, and the signal line and histogram before index slow+signal-2.
+func MACD(klines []market.Kline, fast, slow, signal int) (macd, signalLine, hist []udecimal.Decimal, err error) {
+	if fast <= 0 || signal <= 0 {
+		return nil, nil, nil, errors.NewValidationError("period", "must be positive")
+	}
+	if fast >= slow {
+		return nil, nil, nil, errors.NewValidationError("fast", "must be less than slow")
+	}
+	if err := validatePeriod(len(klines), slow+signal-1); err != nil {
+		return nil, nil, nil, err
+	}
+
+	values := closes(klines)
+	fastEMA, err := ema(values, fast)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	slowEMA, err := ema(values, slow)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	start := slow - 1
+	macd = make([]udecimal.Decimal, len(values))
+	for i := start; i < len(values); i++ {
+		macd[i] = fastEMA[i].Sub(slowEMA[i])
+	}
+
+	sig, err := ema(macd[start:], signal)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	signalLine = make([]udecimal.Decimal, len(values))
+	hist = make([]udecimal.Decimal, len(values))
+	for i := start + signal - 1; i < len(values); i++ {
+		signalLine[i] = sig[i-start]
+		hist[i] = macd[i].Sub(signalLine[i])
+	}
+	return macd, signalLine, hist, nil
+}
+
+// wilder applies one step of Wilder's smoothing.
+func wilder(prev, cur udecimal.Decimal, period int) (udecimal.Decimal, error) {
+	return prev.Mul64(uint64(period - 1)).Add(cur).Div64(uint64(period))
+}
+
+// rsiValue converts average gain and loss to an RSI in [0, 100].
+func rsiValue(avgGain, avgLoss udecimal.Decimal) (udecimal.Decimal, error) {
+	if avgLoss.IsZero() {
+		if avgGain.IsZero() {
+			return udecimal.MustFromInt64(50, 0), nil
+		}
+		return hundred, nil
+	}
+	rs, err := avgGain.Div(avgLoss)
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	frac, err := hundred.Div(rs.Add(udecimal.One))
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	return hundred.Sub(frac), nil
+}
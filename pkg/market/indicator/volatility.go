@@ -0,0 +1,92 @@
+package indicator
+
+import (
+	"fmt"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// BollingerBands returns bands of stddev standard deviations around the SMA of
+// close prices over period. The first period-1 entries are zero.
+//
+// The standard deviation is the population deviation of the window,
+// sqrt(sum((close-mid)^2) / period). udecimal has no floating-point sqrt; it is
+// computed with udecimal.Decimal.Sqrt, an integer Newton iteration on the
+// fixed-point value, so results are exact to 19 decimals and deterministic.
+func BollingerBands(klines []market.Kline, period int, stddev float64) (upper, mid, lower []udecimal.Decimal, err error) {
+	if err := validatePeriod(len(klines), period); err != nil {
+		return nil, nil, nil, err
+	}
+	if stddev <= 0 {
+		return nil, nil, nil, errors.NewValidationError("stddev", "must be positive")
+	}
+	k, err := udecimal.NewFromFloat64(stddev)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("stddev multiplier: %w", err)
+	}
+
+	values := closes(klines)
+	mid, err = sma(values, period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	upper = make([]udecimal.Decimal, len(values))
+	lower = make([]udecimal.Decimal, len(values))
+	for i := period - 1; i < len(values); i++ {
+		var sumSq udecimal.Decimal
+		for _, v := range values[i-period+1 : i+1] {
+			d := v.Sub(mid[i])
+			sumSq = sumSq.Add(d.Mul(d))
+		}
+		variance, err := sumSq.Div64(uint64(period))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("bollinger variance at %d: %w", i, err)
+		}
+		sd, err := variance.Sqrt()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("bollinger stddev at %d: %w", i, err)
+		}
+		width := sd.Mul(k)
+		upper[i] = mid[i].Add(width)
+		lower[i] = mid[i].Sub(width)
+	}
+	return upper, mid, lower, nil
+}
+
+// ATR returns the average true range over period with Wilder's smoothing.
+// The true range of a candle is the largest of high-low, |high-prevClose| and
+// |low-prevClose|, so it starts at the second kline. The first ATR, at index
+// period, is the mean of the first period true ranges; earlier entries are zero.
+func ATR(klines []market.Kline, period int) ([]udecimal.Decimal, error) {
+	if err := validateKlines(len(klines), period, period+1); err != nil {
+		return nil, err
+	}
+
+	out := make([]udecimal.Decimal, len(klines))
+	var atr udecimal.Decimal
+	for i := 1; i < len(klines); i++ {
+		tr := TrueRange(klines[i], klines[i-1].Close)
+		var err error
+		switch {
+		case i < period:
+			atr = atr.Add(tr)
+			continue
+		case i == period:
+			atr, err = atr.Add(tr).Div64(uint64(period))
+		default:
+			atr, err = wilder(atr, tr, period)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("atr at %d: %w", i, err)
+		}
+		out[i] = atr
+	}
+	return out, nil
+}
+
+// TrueRange returns the true range of k given the previous candle's close.
+func TrueRange(k market.Kline, prevClose udecimal.Decimal) udecimal.Decimal {
+	return udecimal.Max(k.Range(), k.High.Sub(prevClose).Abs(), k.Low.Sub(prevClose).Abs())
+}
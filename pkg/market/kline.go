@@ -0,0 +1,193 @@
+package market
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
+)
+
+// FindKlineGaps returns the open times of candles missing from klines, which
+// must be sorted by OpenTime without duplicates.
+func FindKlineGaps(klines []Kline, interval KlineInterval) ([]time.Time, error) {
+	var gaps []time.Time
+	for i := 1; i < len(klines); i++ {
+		missing, err := missingOpenTimes(klines[i-1], klines[i], interval)
+		if err != nil {
+			return nil, fmt.Errorf("kline %d: %w", i, err)
+		}
+		gaps = append(gaps, missing...)
+	}
+	return gaps, nil
+}
+
+// KlineOpenTime returns the open time, in UTC, of the interval candle that
+// contains t. Intervals up to 3d are aligned to the Unix epoch, which puts
+// daily candles at 00:00 UTC; weekly candles open on Monday 00:00 UTC and
+// monthly candles on the first of the month, as on most exchanges.
+func KlineOpenTime(t time.Time, interval KlineInterval) (time.Time, error) {
+	t = t.UTC()
+	switch interval {
+	case Interval1M:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case Interval1w:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	}
+	d, err := interval.Duration()
+	if err != nil {
+		return time.Time{}, err
+	}
+	epoch := time.Unix(0, 0).UTC()
+	offset := t.Sub(epoch)
+	rem := offset % d
+	if rem < 0 {
+		rem += d
+	}
+	return t.Add(-rem), nil
+}
+
+// NextKlineClose returns when the interval candle containing t closes, which
+// is the open time of the next candle. Exchanges stamp CloseTime just before
+// it (e.g. 1ms earlier on Binance).
+func NextKlineClose(t time.Time, interval KlineInterval) (time.Time, error) {
+	open, err := KlineOpenTime(t, interval)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return interval.next(open)
+}
+
+// HeikinAshi returns the Heikin-Ashi series of klines, which must be sorted by
+// OpenTime:
+//
+//	close = (open + high + low + close) / 4
+//	open  = (previous HA open + previous HA close) / 2, or (open + close) / 2 for the first
+//	high  = max(high, HA open, HA close)
+//	low   = min(low, HA open, HA close)
+//
+// Times, volumes and the other fields are copied from the source candle.
+func HeikinAshi(klines []Kline) ([]Kline, error) {
+	if len(klines) == 0 {
+		return nil, nil
+	}
+	ha := make([]Kline, len(klines))
+	for i, k := range klines {
+		haClose, err := k.Open.Add(k.High).Add(k.Low).Add(k.Close).Div64(4)
+		if err != nil {
+			return nil, fmt.Errorf("kline %d: %w", i, err)
+		}
+		haOpen, err := k.Open.Add(k.Close).Div64(2)
+		if i > 0 {
+			haOpen, err = ha[i-1].Open.Add(ha[i-1].Close).Div64(2)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("kline %d: %w", i, err)
+		}
+		h := k
+		h.Open = haOpen
+		h.Close = haClose
+		h.High = udecimal.Max(k.High, haOpen, haClose)
+		h.Low = udecimal.Min(k.Low, haOpen, haClose)
+		ha[i] = h
+	}
+	return ha, nil
+}
+
+// FillKlineGaps returns klines with every missing candle inserted, producing a
+// contiguous series. Inserted candles are flat at the previous close
+// (open = high = low = close) with zero volume and trades, and keep the
+// previous candle's CloseTime offset. klines must be sorted by OpenTime
+// without duplicates.
+func FillKlineGaps(klines []Kline, interval KlineInterval) ([]Kline, error) {
+	if len(klines) == 0 {
+		return nil, nil
+	}
+	filled := make([]Kline, 0, len(klines))
+	filled = append(filled, klines[0])
+	for i := 1; i < len(klines); i++ {
+		prev := klines[i-1]
+		missing, err := missingOpenTimes(prev, klines[i], interval)
+		if err != nil {
+			return nil, fmt.Errorf("kline %d: %w", i, err)
+		}
+		if len(missing) > 0 {
+			// Exchanges report CloseTime either as the next open or just before it.
+			prevNext, err := interval.next(prev.OpenTime)
+			if err != nil {
+				return nil, err
+			}
+			closeGap := prevNext.Sub(prev.CloseTime)
+			for _, open := range missing {
+				next, err := interval.next(open)
+				if err != nil {
+					return nil, err
+				}
+				filled = append(filled, flatKline(prev, open, next.Add(-closeGap)))
+			}
+		}
+		filled = append(filled, klines[i])
+	}
+	return filled, nil
+}
+
+// missingOpenTimes returns the open times strictly between prev and next.
+func missingOpenTimes(prev, next Kline, interval KlineInterval) ([]time.Time, error) {
+	if !next.OpenTime.After(prev.OpenTime) {
+		return nil, errors.NewValidationError("klines", "must be sorted by open time without duplicates")
+	}
+	var missing []time.Time
+	t, err := interval.next(prev.OpenTime)
+	for ; err == nil && t.Before(next.OpenTime); t, err = interval.next(t) {
+		missing = append(missing, t)
+	}
+	return missing, err
+}
+
+// flatKline returns a zero-volume candle at the close of prev.
+func flatKline(prev Kline, openTime, closeTime time.Time) Kline {
+	return Kline{
+		Symbol:    prev.Symbol,
+		Interval:  prev.Interval,
+		OpenTime:  openTime,
+		CloseTime: closeTime,
+		Open:      prev.Close,
+		High:      prev.Close,
+		Low:       prev.Close,
+		Close:     prev.Close,
+		IsClosed:  true,
+	}
+}
+
+// OnlyClosed returns a stream of the klines of src that have closed, one per
+// candle, dropping in-progress updates.
+//
+// A kline is closed when the exchange sets IsClosed. For exchanges that never
+// do, a candle is also taken as closed when an update for a later OpenTime
+// arrives; its last update is then emitted with IsClosed set. src must carry a
+// single symbol and interval.
+func OnlyClosed(src stream.Stream[Kline]) stream.Stream[Kline] {
+	var (
+		last    Kline
+		hasLast bool
+		done    bool // last's candle was already emitted
+	)
+	return stream.Transform(src, func(k Kline, emit func(Kline)) {
+		if hasLast && k.OpenTime.Before(last.OpenTime) {
+			return // Stale update for an earlier candle
+		}
+		if hasLast && !done && k.OpenTime.After(last.OpenTime) {
+			last.IsClosed = true
+			emit(last)
+		}
+		if hasLast && done && k.OpenTime.Equal(last.OpenTime) {
+			return // Repeated update for an emitted candle
+		}
+		last, hasLast, done = k, true, k.IsClosed
+		if k.IsClosed {
+			emit(k)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package market
+
+import (
+	"github.com/quagmt/udecimal"
+)
+
+// oneBps is one basis point (0.0001).
+var oneBps = udecimal.MustFromInt64(1, 4)
+
+// OffsetPrice moves base by bps basis points away from the market for a
+// passive order on side: buys are priced below base, sells above.
+// A negative bps moves toward the market instead. The result is not rounded;
+// use SymbolInfo.OffsetPrice for an exchange-submittable price.
+func OffsetPrice(base, bps udecimal.Decimal, side Side) udecimal.Decimal {
+	offset := base.Mul(bps).Mul(oneBps)
+	if side == SideBuy {
+		return base.Sub(offset)
+	}
+	return base.Add(offset)
+}
+
+// OffsetPrice is OffsetPrice rounded to the symbol's tick size away from the
+// market (down for buys, up for sells), so the offset is never narrowed.
+func (si SymbolInfo) OffsetPrice(base, bps udecimal.Decimal, side Side) udecimal.Decimal {
+	return si.roundPassive(OffsetPrice(base, bps, side), side)
+}
+
+// OffsetTicks aligns base to the tick size away from the market and then moves
+// it ticks increments further: buys below base, sells above. A negative ticks
+// moves toward the market instead.
+func (si SymbolInfo) OffsetTicks(base udecimal.Decimal, ticks int, side Side) udecimal.Decimal {
+	offset := si.TickSize.Mul(udecimal.MustFromInt64(int64(ticks), 0))
+	price := si.roundPassive(base, side)
+	if side == SideBuy {
+		return price.Sub(offset)
+	}
+	return price.Add(offset)
+}
+
+// roundPassive rounds price to the tick size, down for buys and up for sells.
+func (si SymbolInfo) roundPassive(price udecimal.Decimal, side Side) udecimal.Decimal {
+	if side == SideBuy {
+		return si.RoundPrice(price)
+	}
+	return roundUp(price, si.TickSize, si.PricePrecision)
+}
@@ -0,0 +1,160 @@
+package market
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
+)
+
+// OrderBookDiff is an incremental order book update.
+// A level with zero quantity removes that price from the book.
+type OrderBookDiff struct {
+	Symbol        Symbol           `json:"symbol"`
+	Bids          []OrderBookEntry `json:"bids"`
+	Asks          []OrderBookEntry `json:"asks"`
+	FirstSequence uint64           `json:"first_sequence"`     // First update ID covered by this diff
+	FinalSequence uint64           `json:"final_sequence"`     // Last update ID covered by this diff
+	Checksum      uint32           `json:"checksum,omitempty"` // Exchange checksum of the book after this diff; 0 if not sent
+	Timestamp     time.Time        `json:"timestamp"`
+}
+
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (d OrderBookDiff) ExchangeTime() time.Time {
+	return d.Timestamp
+}
+
+// Merge combines d with the diff that follows it into a single diff that has
+// the same effect as applying both in order. Later levels replace earlier ones
+// at the same price, and removals are kept so they still apply.
+// A next already covered by d's FinalSequence is stale and d is returned
+// unchanged, as ApplyDiff would ignore it. Returns an error wrapping
+// errors.ErrSequenceGap if next does not continue d.
+func (d OrderBookDiff) Merge(next OrderBookDiff) (OrderBookDiff, error) {
+	if d.FinalSequence != 0 && next.FinalSequence != 0 && next.FinalSequence <= d.FinalSequence {
+		return d, nil
+	}
+	if d.FinalSequence != 0 && next.FirstSequence > d.FinalSequence+1 {
+		return d, fmt.Errorf("%w: merge %d after %d", errors.ErrSequenceGap, next.FirstSequence, d.FinalSequence)
+	}
+	merged := OrderBookDiff{
+		Symbol:        d.Symbol,
+		Bids:          mergeLevels(d.Bids, next.Bids, true),
+		Asks:          mergeLevels(d.Asks, next.Asks, false),
+		FirstSequence: d.FirstSequence,
+		FinalSequence: max(d.FinalSequence, next.FinalSequence),
+		Checksum:      next.Checksum,
+		Timestamp:     next.Timestamp,
+	}
+	return merged, nil
+}
+
+// ApplyDiff applies an incremental update to the book in place, keeping bids
+// sorted descending and asks ascending.
+//
+// Diffs already covered by the book's Sequence are ignored. If the diff starts
+// after Sequence+1 the book is left unchanged and an error wrapping
+// errors.ErrSequenceGap is returned; the book must then be re-snapshotted.
+// Sequence checks are skipped when either side carries no sequence.
+func (ob *OrderBook) ApplyDiff(diff OrderBookDiff) error {
+	if ob.Sequence != 0 && diff.FinalSequence != 0 {
+		if diff.FinalSequence <= ob.Sequence {
+			return nil
+		}
+		if diff.FirstSequence > ob.Sequence+1 {
+			return fmt.Errorf("%w: diff starts at %d, book at %d", errors.ErrSequenceGap, diff.FirstSequence, ob.Sequence)
+		}
+	}
+	ob.Bids = applyLevels(ob.Bids, diff.Bids, true, false)
+	ob.Asks = applyLevels(ob.Asks, diff.Asks, false, false)
+	if diff.FinalSequence != 0 {
+		ob.Sequence = diff.FinalSequence
+	}
+	if !diff.Timestamp.IsZero() {
+		ob.Timestamp = diff.Timestamp
+	}
+	return nil
+}
+
+// ApplyDiffAndTrim applies diff and then trims the book to maxDepth levels per
+// side (see Trim). Use it on long-lived books to bound their size.
+func (ob *OrderBook) ApplyDiffAndTrim(diff OrderBookDiff, maxDepth int) error {
+	if err := ob.ApplyDiff(diff); err != nil {
+		return err
+	}
+	ob.Trim(maxDepth)
+	return nil
+}
+
+// Trim keeps the best depth bids and asks, dropping the worst-priced levels.
+// Levels must be sorted (bids descending, asks ascending). A depth <= 0 keeps
+// every level.
+func (ob *OrderBook) Trim(depth int) {
+	if depth <= 0 {
+		return
+	}
+	if len(ob.Bids) > depth {
+		ob.Bids = ob.Bids[:depth]
+	}
+	if len(ob.Asks) > depth {
+		ob.Asks = ob.Asks[:depth]
+	}
+}
+
+// Clone returns a deep copy of the book, safe to hand to another goroutine.
+func (ob OrderBook) Clone() OrderBook {
+	ob.Bids = slices.Clone(ob.Bids)
+	ob.Asks = slices.Clone(ob.Asks)
+	return ob
+}
+
+// mergeLevels applies the levels of b on top of a, keeping zero-quantity removals.
+func mergeLevels(a, b []OrderBookEntry, desc bool) []OrderBookEntry {
+	merged := slices.Clone(a)
+	slices.SortFunc(merged, func(x, y OrderBookEntry) int { return compareLevel(x.Price, y.Price, desc) })
+	return applyLevels(merged, b, desc, true)
+}
+
+// applyLevels applies updates to sorted levels. A zero quantity deletes the
+// level unless keepZero is set.
+func applyLevels(levels, updates []OrderBookEntry, desc, keepZero bool) []OrderBookEntry {
+	for _, u := range updates {
+		i, found := slices.BinarySearchFunc(levels, u.Price, func(e OrderBookEntry, p udecimal.Decimal) int {
+			return compareLevel(e.Price, p, desc)
+		})
+		switch {
+		case u.Qty.IsZero() && !keepZero:
+			if found {
+				levels = slices.Delete(levels, i, i+1)
+			}
+		case found:
+			levels[i].Qty = u.Qty
+		default:
+			levels = slices.Insert(levels, i, u)
+		}
+	}
+	return levels
+}
+
+// compareLevel orders prices ascending, or descending when desc is set.
+func compareLevel(a, b udecimal.Decimal, desc bool) int {
+	if desc {
+		return b.Cmp(a)
+	}
+	return a.Cmp(b)
+}
+
+// TrimBooks returns a stream of the books of src trimmed to depth levels per
+// side (see Trim). A depth <= 0 returns src unchanged.
+func TrimBooks(src stream.Stream[OrderBook], depth int) stream.Stream[OrderBook] {
+	if depth <= 0 {
+		return src
+	}
+	return stream.Transform(src, func(ob OrderBook, emit func(OrderBook)) {
+		ob.Trim(depth)
+		emit(ob)
+	})
+}
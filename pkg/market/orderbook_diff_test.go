@@ -0,0 +1,50 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/quagmt/udecimal"
+)
+
+// TestOrderBookDiffMergeStale checks that a diff already covered by the
+// pending one is dropped rather than letting its old levels overwrite newer
+// ones.
+func TestOrderBookDiffMergeStale(t *testing.T) {
+	pending := OrderBookDiff{
+		Symbol:        "BTCUSDT",
+		Bids:          []OrderBookEntry{{Price: udecimal.MustParse("100"), Qty: udecimal.MustParse("2")}},
+		FirstSequence: 11,
+		FinalSequence: 20,
+	}
+	stale := OrderBookDiff{
+		Symbol:        "BTCUSDT",
+		Bids:          []OrderBookEntry{{Price: udecimal.MustParse("100"), Qty: udecimal.MustParse("1")}},
+		FirstSequence: 15,
+		FinalSequence: 18,
+	}
+
+	merged, err := pending.Merge(stale)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if merged.FirstSequence != 11 || merged.FinalSequence != 20 {
+		t.Fatalf("sequences = %d-%d, want 11-20", merged.FirstSequence, merged.FinalSequence)
+	}
+	if len(merged.Bids) != 1 || !merged.Bids[0].Qty.Equal(udecimal.MustParse("2")) {
+		t.Fatalf("bids = %v, want the pending 2@100", merged.Bids)
+	}
+
+	next := OrderBookDiff{
+		Symbol:        "BTCUSDT",
+		Bids:          []OrderBookEntry{{Price: udecimal.MustParse("100"), Qty: udecimal.MustParse("3")}},
+		FirstSequence: 21,
+		FinalSequence: 25,
+	}
+	merged, err = pending.Merge(next)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if merged.FinalSequence != 25 || !merged.Bids[0].Qty.Equal(udecimal.MustParse("3")) {
+		t.Fatalf("merged = %d %v, want 25 with 3@100", merged.FinalSequence, merged.Bids)
+	}
+}
@@ -0,0 +1,83 @@
+package market
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TopDepth is the number of levels per side held by a BookSnapshot.
+const TopDepth = 20
+
+// BookSnapshot is a fixed-size value copy of the top of an order book.
+// It holds no slices, so copying it never aliases the source book and reading
+// it never allocates. Only the first BidCount bids and AskCount asks are set.
+type BookSnapshot struct {
+	Symbol    Symbol
+	Bids      [TopDepth]OrderBookEntry // Sorted by price descending
+	Asks      [TopDepth]OrderBookEntry // Sorted by price ascending
+	BidCount  int
+	AskCount  int
+	Sequence  uint64
+	Timestamp time.Time
+}
+
+// TopN copies the best n levels per side (at most TopDepth) into a snapshot.
+func (ob OrderBook) TopN(n int) BookSnapshot {
+	n = min(max(n, 0), TopDepth)
+	s := BookSnapshot{
+		Symbol:    ob.Symbol,
+		Sequence:  ob.Sequence,
+		Timestamp: ob.Timestamp,
+	}
+	s.BidCount = copy(s.Bids[:n], ob.Bids)
+	s.AskCount = copy(s.Asks[:n], ob.Asks)
+	return s
+}
+
+// BestBid returns the best bid, or false if there is none.
+func (s *BookSnapshot) BestBid() (OrderBookEntry, bool) {
+	if s.BidCount == 0 {
+		return OrderBookEntry{}, false
+	}
+	return s.Bids[0], true
+}
+
+// BestAsk returns the best ask, or false if there is none.
+func (s *BookSnapshot) BestAsk() (OrderBookEntry, bool) {
+	if s.AskCount == 0 {
+		return OrderBookEntry{}, false
+	}
+	return s.Asks[0], true
+}
+
+// BookPublisher shares the top of a book between one writer and many readers
+// without locks. The writer (typically the goroutine consuming a BookManager
+// or order book stream) calls Publish after every update; readers call Load
+// and get the latest immutable snapshot. Snapshots are never modified after
+// publishing, so readers may keep them as long as they like.
+//
+//	var pub market.BookPublisher
+//	go func() {
+//		for book := range books {
+//			pub.Publish(book, 5)
+//		}
+//	}()
+//	...
+//	if snap := pub.Load(); snap != nil {
+//		bid, _ := snap.BestBid()
+//	}
+type BookPublisher struct {
+	snap atomic.Pointer[BookSnapshot]
+}
+
+// Publish stores the top depth levels of ob as the latest snapshot.
+func (p *BookPublisher) Publish(ob OrderBook, depth int) {
+	snap := ob.TopN(depth)
+	p.snap.Store(&snap)
+}
+
+// Load returns the latest snapshot, or nil if nothing was published yet.
+// The snapshot must not be modified.
+func (p *BookPublisher) Load() *BookSnapshot {
+	return p.snap.Load()
+}
@@ -0,0 +1,91 @@
+package market
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/quagmt/udecimal"
+)
+
+// SymbolFilter selects symbols by chaining predicates; a symbol matches when
+// every predicate does. The zero value matches everything, and each chained
+// call returns a new filter, leaving the receiver unchanged.
+//
+// Example:
+//
+//	f := market.SymbolFilter{}.QuoteAsset("USDT").Contains("BTC").Trading()
+//	usdt := market.FilterSymbolInfo(infos, f)
+type SymbolFilter struct {
+	preds []func(SymbolInfo) bool
+}
+
+// Where adds a custom predicate.
+func (f SymbolFilter) Where(pred func(SymbolInfo) bool) SymbolFilter {
+	return SymbolFilter{preds: append(slices.Clip(f.preds), pred)}
+}
+
+// QuoteAsset keeps symbols quoted in the given asset (case-insensitive).
+func (f SymbolFilter) QuoteAsset(quote string) SymbolFilter {
+	return f.Where(func(si SymbolInfo) bool { return strings.EqualFold(si.QuoteAsset, quote) })
+}
+
+// BaseAsset keeps symbols with the given base asset (case-insensitive).
+func (f SymbolFilter) BaseAsset(base string) SymbolFilter {
+	return f.Where(func(si SymbolInfo) bool { return strings.EqualFold(si.BaseAsset, base) })
+}
+
+// Contains keeps symbols whose name contains substr (case-insensitive).
+func (f SymbolFilter) Contains(substr string) SymbolFilter {
+	substr = strings.ToUpper(substr)
+	return f.Where(func(si SymbolInfo) bool { return strings.Contains(si.Symbol.String(), substr) })
+}
+
+// Trading keeps symbols whose status allows placing orders.
+func (f SymbolFilter) Trading() SymbolFilter {
+	return f.Where(func(si SymbolInfo) bool { return si.Status.IsTrading() })
+}
+
+// MaxMinNotional keeps symbols whose minimum order notional is at most n.
+func (f SymbolFilter) MaxMinNotional(n udecimal.Decimal) SymbolFilter {
+	return f.Where(func(si SymbolInfo) bool { return si.MinNotional.LessThanOrEqual(n) })
+}
+
+// Match returns true if info satisfies every predicate.
+func (f SymbolFilter) Match(info SymbolInfo) bool {
+	for _, pred := range f.preds {
+		if !pred(info) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchSymbol matches a bare symbol. Its base and quote assets are inferred
+// with Symbol.Base and Symbol.Quote, and rule predicates see zero-valued
+// rules (an unknown status, which Trading rejects, and zero min notional), so
+// prefer FilterSymbolInfo when exchange info is available.
+func (f SymbolFilter) MatchSymbol(s Symbol) bool {
+	return f.Match(SymbolInfo{Symbol: s, BaseAsset: s.Base(), QuoteAsset: s.Quote()})
+}
+
+// FilterSymbols returns the symbols matching f, in their original order.
+func FilterSymbols(symbols []Symbol, f SymbolFilter) []Symbol {
+	var out []Symbol
+	for _, s := range symbols {
+		if f.MatchSymbol(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// FilterSymbolInfo returns the symbol info entries matching f, in their original order.
+func FilterSymbolInfo(infos []SymbolInfo, f SymbolFilter) []SymbolInfo {
+	var out []SymbolInfo
+	for _, info := range infos {
+		if f.Match(info) {
+			out = append(out, info)
+		}
+	}
+	return out
+}
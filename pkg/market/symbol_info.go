@@ -0,0 +1,187 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/quagmt/udecimal"
+)
+
+// SymbolStatus represents the trading status of a symbol. The zero value is
+// SymbolStatusUnknown, so a SymbolInfo whose status was never set is not
+// treated as trading.
+type SymbolStatus int
+
+const (
+	SymbolStatusUnknown    SymbolStatus = iota // Not reported or not recognized
+	SymbolStatusTrading                        // Open for trading
+	SymbolStatusPreTrading                     // Listed but not yet open for trading
+	SymbolStatusBreak                          // Temporarily not trading (break, auction, end of day)
+	SymbolStatusHalted                         // Trading halted by the exchange
+	SymbolStatusClosed                         // Delisted or settled
+)
+
+// String implements fmt.Stringer.
+func (s SymbolStatus) String() string {
+	switch s {
+	case SymbolStatusTrading:
+		return "TRADING"
+	case SymbolStatusPreTrading:
+		return "PRE_TRADING"
+	case SymbolStatusBreak:
+		return "BREAK"
+	case SymbolStatusHalted:
+		return "HALTED"
+	case SymbolStatusClosed:
+		return "CLOSED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s SymbolStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *SymbolStatus) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "UNKNOWN":
+		*s = SymbolStatusUnknown
+	case "TRADING":
+		*s = SymbolStatusTrading
+	case "PRE_TRADING", "PRELAUNCH", "PRE_LAUNCH":
+		*s = SymbolStatusPreTrading
+	case "BREAK", "POST_TRADING", "END_OF_DAY", "AUCTION_MATCH":
+		*s = SymbolStatusBreak
+	case "HALT", "HALTED":
+		*s = SymbolStatusHalted
+	case "CLOSED", "DELISTED", "DELIVERING", "SETTLING":
+		*s = SymbolStatusClosed
+	default:
+		return errors.NewValidationError("status", fmt.Sprintf("unknown symbol status: %s", string(text)))
+	}
+	return nil
+}
+
+// IsTrading returns true if orders can currently be placed on the symbol.
+func (s SymbolStatus) IsTrading() bool {
+	return s == SymbolStatusTrading
+}
+
+// SymbolInfo describes the trading rules of a symbol as published by the exchange.
+type SymbolInfo struct {
+	Symbol            Symbol           `json:"symbol"`
+	BaseAsset         string           `json:"base_asset"`
+	QuoteAsset        string           `json:"quote_asset"`
+	Status            SymbolStatus     `json:"status"`
+	PricePrecision    uint8            `json:"price_precision"`    // Decimal places allowed for prices
+	QuantityPrecision uint8            `json:"quantity_precision"` // Decimal places allowed for quantities
+	TickSize          udecimal.Decimal `json:"tick_size"`          // Minimum price increment
+	StepSize          udecimal.Decimal `json:"step_size"`          // Minimum quantity increment
+	MinQty            udecimal.Decimal `json:"min_qty"`
+	MaxQty            udecimal.Decimal `json:"max_qty"`
+	MinNotional       udecimal.Decimal `json:"min_notional"`
+
+	// PriceMultiplierUp and PriceMultiplierDown bound limit prices relative
+	// to a reference price (Binance PERCENT_PRICE): a price must lie within
+	// [ref*PriceMultiplierDown, ref*PriceMultiplierUp]. Zero = no bound.
+	PriceMultiplierUp   udecimal.Decimal `json:"price_multiplier_up,omitempty"`
+	PriceMultiplierDown udecimal.Decimal `json:"price_multiplier_down,omitempty"`
+}
+
+// RoundPrice rounds a price down to the nearest tick and price precision.
+func (si SymbolInfo) RoundPrice(price udecimal.Decimal) udecimal.Decimal {
+	return roundDown(price, si.TickSize, si.PricePrecision)
+}
+
+// RoundQty rounds a quantity down to the nearest step and quantity precision.
+func (si SymbolInfo) RoundQty(qty udecimal.Decimal) udecimal.Decimal {
+	return roundDown(qty, si.StepSize, si.QuantityPrecision)
+}
+
+// ValidatePrecision is the strict alternative to RoundPrice and RoundQty: it
+// returns a ValidationError naming "price" or "quantity" if the value is not a
+// multiple of the tick or step size or has more decimals than the symbol
+// allows. A zero price (market order) is not checked.
+func (si SymbolInfo) ValidatePrecision(price, qty udecimal.Decimal) error {
+	if !price.IsZero() && !roundDown(price, si.TickSize, si.PricePrecision).Equal(price) {
+		return errors.NewValidationError("price", fmt.Sprintf("%s is not a multiple of tick size %s at %d decimals", price, si.TickSize, si.PricePrecision))
+	}
+	if !roundDown(qty, si.StepSize, si.QuantityPrecision).Equal(qty) {
+		return errors.NewValidationError("quantity", fmt.Sprintf("%s is not a multiple of step size %s at %d decimals", qty, si.StepSize, si.QuantityPrecision))
+	}
+	return nil
+}
+
+// HasPriceBand returns true if the symbol bounds prices relative to a
+// reference price.
+func (si SymbolInfo) HasPriceBand() bool {
+	return !si.PriceMultiplierUp.IsZero() || !si.PriceMultiplierDown.IsZero()
+}
+
+// ValidatePriceAgainst checks price against the symbol's price band around
+// refPrice, typically the last or mark price, and returns a ValidationError
+// naming "price" if the exchange would reject it. Symbols without a band
+// accept any price; with one, refPrice must be positive.
+func (si SymbolInfo) ValidatePriceAgainst(price, refPrice udecimal.Decimal) error {
+	if !si.HasPriceBand() {
+		return nil
+	}
+	if !refPrice.IsPos() {
+		return errors.NewValidationError("ref_price", "must be positive")
+	}
+	if up := refPrice.Mul(si.PriceMultiplierUp); !si.PriceMultiplierUp.IsZero() && price.GreaterThan(up) {
+		return errors.NewValidationError("price", fmt.Sprintf("%s is above the band limit %s (%s x %s)", price, up, refPrice, si.PriceMultiplierUp))
+	}
+	if down := refPrice.Mul(si.PriceMultiplierDown); price.LessThan(down) {
+		return errors.NewValidationError("price", fmt.Sprintf("%s is below the band limit %s (%s x %s)", price, down, refPrice, si.PriceMultiplierDown))
+	}
+	return nil
+}
+
+// FixedPrice returns the price as a FixedDecimal at the symbol's price precision.
+func (si SymbolInfo) FixedPrice(price udecimal.Decimal) FixedDecimal {
+	return NewFixedDecimal(price, si.PricePrecision)
+}
+
+// FixedQty returns the quantity as a FixedDecimal at the symbol's quantity precision.
+func (si SymbolInfo) FixedQty(qty udecimal.Decimal) FixedDecimal {
+	return NewFixedDecimal(qty, si.QuantityPrecision)
+}
+
+// FormatPrice returns price as the exchange writes it: fixed-point at the
+// symbol's price precision, with trailing zeros and without exponent. A price
+// with more decimals than allowed keeps them rather than being rounded.
+func (si SymbolInfo) FormatPrice(price udecimal.Decimal) string {
+	return si.FixedPrice(price).String()
+}
+
+// FormatQty is FormatPrice for quantities at the symbol's quantity precision.
+func (si SymbolInfo) FormatQty(qty udecimal.Decimal) string {
+	return si.FixedQty(qty).String()
+}
+
+// roundDown truncates v to a multiple of increment (if non-zero) and to prec decimals.
+func roundDown(v, increment udecimal.Decimal, prec uint8) udecimal.Decimal {
+	if !increment.IsZero() {
+		if _, rem, err := v.QuoRem(increment); err == nil {
+			v = v.Sub(rem)
+		}
+	}
+	return v.Trunc(prec)
+}
+
+// roundUp is roundDown rounding up to the next increment instead.
+func roundUp(v, increment udecimal.Decimal, prec uint8) udecimal.Decimal {
+	down := roundDown(v, increment, prec)
+	if down.Equal(v) {
+		return down
+	}
+	if increment.IsZero() {
+		increment = udecimal.MustFromInt64(1, prec)
+	}
+	return down.Add(increment)
+}
@@ -5,6 +5,7 @@ package market
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -110,6 +111,14 @@ func (s *Side) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Opposite returns the other side: SideSell for SideBuy and vice versa.
+func (s Side) Opposite() Side {
+	if s == SideBuy {
+		return SideSell
+	}
+	return SideBuy
+}
+
 // ParseSide parses a string into a Side.
 func ParseSide(s string) (Side, error) {
 	var side Side
@@ -136,6 +145,21 @@ type Ticker struct {
 	Timestamp     time.Time       `json:"timestamp"`
 }
 
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (t Ticker) ExchangeTime() time.Time {
+	return t.Timestamp
+}
+
+// Normalize rescales the prices and the touch quantities to the symbol's
+// precision (see NormalizeDecimal). Volumes and 24h change are left as sent.
+func (t *Ticker) Normalize(info SymbolInfo) {
+	for _, p := range []*udecimal.Decimal{&t.LastPrice, &t.BidPrice, &t.AskPrice, &t.High24h, &t.Low24h} {
+		*p = NormalizeDecimal(*p, info.PricePrecision)
+	}
+	t.BidQty = NormalizeDecimal(t.BidQty, info.QuantityPrecision)
+	t.AskQty = NormalizeDecimal(t.AskQty, info.QuantityPrecision)
+}
+
 // Spread returns the bid-ask spread (ask - bid).
 func (t Ticker) Spread() udecimal.Decimal {
 	return t.AskPrice.Sub(t.BidPrice)
@@ -159,6 +183,26 @@ func (t Ticker) SpreadPercent() (udecimal.Decimal, error) {
 	return t.Spread().Div(mid)
 }
 
+// SpreadBps returns the spread in basis points of the mid-price (spread / mid * 10000).
+func (t Ticker) SpreadBps() (udecimal.Decimal, error) {
+	pct, err := t.SpreadPercent()
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	return pct.Mul64(10000), nil
+}
+
+// WeightedMid returns the size-weighted mid-price
+// (bid*askQty + ask*bidQty) / (bidQty + askQty), which leans toward the side
+// with less resting size.
+func (t Ticker) WeightedMid() (udecimal.Decimal, error) {
+	totalQty := t.BidQty.Add(t.AskQty)
+	if totalQty.IsZero() {
+		return udecimal.Decimal{}, errors.NewValidationError("qty", "bid and ask quantities are zero")
+	}
+	return t.BidPrice.Mul(t.AskQty).Add(t.AskPrice.Mul(t.BidQty)).Div(totalQty)
+}
+
 // OrderBookEntry represents a single price level in the order book.
 type OrderBookEntry struct {
 	Price udecimal.Decimal `json:"price"`
@@ -179,6 +223,11 @@ type OrderBook struct {
 	Sequence  uint64             `json:"sequence"`
 }
 
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (ob OrderBook) ExchangeTime() time.Time {
+	return ob.Timestamp
+}
+
 // BestBid returns the best (highest) bid entry, or nil if empty.
 func (ob OrderBook) BestBid() *OrderBookEntry {
 	if len(ob.Bids) == 0 {
@@ -210,6 +259,70 @@ func (ob OrderBook) Depth() (bids, asks int) {
 	return len(ob.Bids), len(ob.Asks)
 }
 
+// IsCrossed returns true if the best bid is above the best ask, which a
+// consistent book never shows. It is false if either side is empty.
+func (ob OrderBook) IsCrossed() bool {
+	bestBid, bestAsk := ob.BestBid(), ob.BestAsk()
+	return bestBid != nil && bestAsk != nil && bestBid.Price.GreaterThan(bestAsk.Price)
+}
+
+// IsLocked returns true if the best bid equals the best ask.
+// It is false if either side is empty.
+func (ob OrderBook) IsLocked() bool {
+	bestBid, bestAsk := ob.BestBid(), ob.BestAsk()
+	return bestBid != nil && bestAsk != nil && bestBid.Price.Equal(bestAsk.Price)
+}
+
+// Sort orders bids by price descending and asks ascending, the invariant
+// Best*, MarketImpact and ApplyDiff rely on. Levels at equal prices keep their
+// relative order.
+func (ob *OrderBook) Sort() {
+	slices.SortStableFunc(ob.Bids, func(x, y OrderBookEntry) int { return compareLevel(x.Price, y.Price, true) })
+	slices.SortStableFunc(ob.Asks, func(x, y OrderBookEntry) int { return compareLevel(x.Price, y.Price, false) })
+}
+
+// Validate checks that every price is positive, no quantity is negative, and
+// each side is strictly sorted: bids descending and asks ascending with no
+// repeated price. It does not reject a crossed book (see IsCrossed).
+func (ob OrderBook) Validate() error {
+	if err := validateLevels("bids", ob.Bids, true); err != nil {
+		return err
+	}
+	return validateLevels("asks", ob.Asks, false)
+}
+
+// Normalize rescales every price and quantity to the symbol's PricePrecision
+// and QuantityPrecision (see NormalizeDecimal). Call it on ingest so books
+// from different venues compare predictably.
+func (ob *OrderBook) Normalize(info SymbolInfo) {
+	normalizeLevels(ob.Bids, info)
+	normalizeLevels(ob.Asks, info)
+}
+
+// normalizeLevels normalizes one side of a book in place.
+func normalizeLevels(levels []OrderBookEntry, info SymbolInfo) {
+	for i := range levels {
+		levels[i].Price = NormalizeDecimal(levels[i].Price, info.PricePrecision)
+		levels[i].Qty = NormalizeDecimal(levels[i].Qty, info.QuantityPrecision)
+	}
+}
+
+// validateLevels checks one side of a book for Validate.
+func validateLevels(field string, levels []OrderBookEntry, desc bool) error {
+	for i, l := range levels {
+		if !l.Price.IsPos() {
+			return errors.NewValidationError(field, fmt.Sprintf("level %d: price %s must be positive", i, l.Price))
+		}
+		if l.Qty.IsNeg() {
+			return errors.NewValidationError(field, fmt.Sprintf("level %d: qty %s is negative", i, l.Qty))
+		}
+		if i > 0 && compareLevel(levels[i-1].Price, l.Price, desc) >= 0 {
+			return errors.NewValidationError(field, fmt.Sprintf("level %d: price %s out of order after %s", i, l.Price, levels[i-1].Price))
+		}
+	}
+	return nil
+}
+
 // Trade represents a normalized trade execution.
 type Trade struct {
 	ID            string           `json:"id"`
@@ -221,11 +334,44 @@ type Trade struct {
 	IsBuyerMaker  bool             `json:"is_buyer_maker"`
 }
 
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (t Trade) ExchangeTime() time.Time {
+	return t.Timestamp
+}
+
+// Normalize rescales the price and quantity to the symbol's precision (see
+// NormalizeDecimal).
+func (t *Trade) Normalize(info SymbolInfo) {
+	t.Price = NormalizeDecimal(t.Price, info.PricePrecision)
+	t.Qty = NormalizeDecimal(t.Qty, info.QuantityPrecision)
+}
+
 // Value returns the trade value (price * qty).
 func (t Trade) Value() udecimal.Decimal {
 	return t.Price.Mul(t.Qty)
 }
 
+// AggressorSide returns the side of the taker. When the buyer is the maker,
+// the taker sold into the bid and the trade is a sell; otherwise it is a buy.
+func (t Trade) AggressorSide() Side {
+	if t.IsBuyerMaker {
+		return SideSell
+	}
+	return SideBuy
+}
+
+// TradeFlow returns the base volume of trades split by aggressor side.
+func TradeFlow(trades []Trade) (buyVol, sellVol udecimal.Decimal) {
+	for _, t := range trades {
+		if t.AggressorSide() == SideBuy {
+			buyVol = buyVol.Add(t.Qty)
+		} else {
+			sellVol = sellVol.Add(t.Qty)
+		}
+	}
+	return buyVol, sellVol
+}
+
 // KlineInterval represents a kline/candlestick interval.
 type KlineInterval string
 
@@ -252,6 +398,48 @@ func (i KlineInterval) String() string {
 	return string(i)
 }
 
+// klineDurations maps fixed-length intervals to their duration.
+var klineDurations = map[KlineInterval]time.Duration{
+	Interval1m:  time.Minute,
+	Interval3m:  3 * time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval30m: 30 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval2h:  2 * time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval6h:  6 * time.Hour,
+	Interval8h:  8 * time.Hour,
+	Interval12h: 12 * time.Hour,
+	Interval1d:  24 * time.Hour,
+	Interval3d:  3 * 24 * time.Hour,
+	Interval1w:  7 * 24 * time.Hour,
+}
+
+// Duration returns the length of the interval. Interval1M has no fixed length
+// and returns an error, as does an unknown interval.
+func (i KlineInterval) Duration() (time.Duration, error) {
+	if d, ok := klineDurations[i]; ok {
+		return d, nil
+	}
+	if i == Interval1M {
+		return 0, errors.NewValidationError("interval", "1M has no fixed duration")
+	}
+	return 0, errors.NewValidationError("interval", fmt.Sprintf("unknown kline interval: %s", string(i)))
+}
+
+// next returns the open time of the candle following the one opening at t.
+func (i KlineInterval) next(t time.Time) (time.Time, error) {
+	if i == Interval1M {
+		return t.AddDate(0, 1, 0), nil
+	}
+	d, err := i.Duration()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(d), nil
+}
+
 // Kline represents a normalized candlestick/OHLCV data point.
 type Kline struct {
 	Symbol      Symbol           `json:"symbol"`
@@ -281,6 +469,12 @@ func (k Kline) ChangePercent() (udecimal.Decimal, error) {
 	return k.Change().Div(k.Open)
 }
 
+// ChangePercentOr is like ChangePercent but returns fallback if the open price
+// is zero.
+func (k Kline) ChangePercentOr(fallback udecimal.Decimal) udecimal.Decimal {
+	return SafeDiv(k.Change(), k.Open, fallback)
+}
+
 // Range returns the price range (high - low).
 func (k Kline) Range() udecimal.Decimal {
 	return k.High.Sub(k.Low)
@@ -294,6 +488,12 @@ func (k Kline) VWAP() (udecimal.Decimal, error) {
 	return k.QuoteVolume.Div(k.Volume)
 }
 
+// TypicalPrice returns (high + low + close) / 3.
+func (k Kline) TypicalPrice() udecimal.Decimal {
+	tp, _ := k.High.Add(k.Low).Add(k.Close).Div64(3) // Only fails dividing by zero
+	return tp
+}
+
 // IsBullish returns true if the candle is bullish (close > open).
 func (k Kline) IsBullish() bool {
 	return k.Close.GreaterThan(k.Open)
@@ -0,0 +1,73 @@
+package market
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quagmt/udecimal"
+)
+
+// FuzzOrderBookSort checks that Sort restores the book invariant for levels
+// in any order: each side ends up a sorted permutation of its input, and a
+// book without repeated prices then passes Validate.
+func FuzzOrderBookSort(f *testing.F) {
+	f.Add([]byte{1, 2, 3}, []byte{3, 2, 1})
+	f.Add([]byte{5, 1, 4, 1, 9}, []byte{2, 6, 5, 3, 5})
+	f.Add([]byte{}, []byte{200})
+
+	f.Fuzz(func(t *testing.T, bidPrices, askPrices []byte) {
+		ob := OrderBook{Bids: fuzzLevels(bidPrices), Asks: fuzzLevels(askPrices)}
+		bids, asks := slices.Clone(ob.Bids), slices.Clone(ob.Asks)
+
+		ob.Sort()
+
+		checkSorted(t, "bids", ob.Bids, bids, true)
+		checkSorted(t, "asks", ob.Asks, asks, false)
+		if distinct(bidPrices) && distinct(askPrices) {
+			if err := ob.Validate(); err != nil {
+				t.Fatalf("Validate after Sort: %v", err)
+			}
+		}
+	})
+}
+
+// fuzzLevels builds one level per byte, priced at the byte value plus one so
+// every price is positive.
+func fuzzLevels(prices []byte) []OrderBookEntry {
+	levels := make([]OrderBookEntry, len(prices))
+	for i, p := range prices {
+		levels[i] = OrderBookEntry{
+			Price: udecimal.MustFromInt64(int64(p)+1, 1),
+			Qty:   udecimal.MustFromInt64(int64(i)+1, 0),
+		}
+	}
+	return levels
+}
+
+func checkSorted(t *testing.T, side string, got, input []OrderBookEntry, desc bool) {
+	t.Helper()
+	if len(got) != len(input) {
+		t.Fatalf("%s: got %d levels, want %d", side, len(got), len(input))
+	}
+	for i := 1; i < len(got); i++ {
+		if compareLevel(got[i-1].Price, got[i].Price, desc) > 0 {
+			t.Fatalf("%s: level %d price %s out of order after %s", side, i, got[i].Price, got[i-1].Price)
+		}
+	}
+	for _, l := range input {
+		if !slices.Contains(got, l) {
+			t.Fatalf("%s: level %s@%s lost by Sort", side, l.Qty, l.Price)
+		}
+	}
+}
+
+func distinct(b []byte) bool {
+	seen := make(map[byte]bool, len(b))
+	for _, v := range b {
+		if seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package market
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/quagmt/udecimal"
+)
+
+// VolumeBucket is the traded base volume within [PriceLow, PriceHigh), split
+// by aggressor side.
+type VolumeBucket struct {
+	PriceLow   udecimal.Decimal `json:"price_low"`
+	PriceHigh  udecimal.Decimal `json:"price_high"`
+	BuyVolume  udecimal.Decimal `json:"buy_volume"`
+	SellVolume udecimal.Decimal `json:"sell_volume"`
+}
+
+// Volume returns the total volume of the bucket.
+func (b VolumeBucket) Volume() udecimal.Decimal {
+	return b.BuyVolume.Add(b.SellVolume)
+}
+
+// Delta returns the buy volume minus the sell volume.
+func (b VolumeBucket) Delta() udecimal.Decimal {
+	return b.BuyVolume.Sub(b.SellVolume)
+}
+
+// VolumeProfile aggregates trade quantities into price buckets of bucketSize,
+// aligned to multiples of bucketSize, and splits each by AggressorSide.
+// Prices must be positive; a trade without one is a validation error.
+// Only buckets that traded are returned, sorted by price ascending.
+func VolumeProfile(trades []Trade, bucketSize udecimal.Decimal) ([]VolumeBucket, error) {
+	if !bucketSize.IsPos() {
+		return nil, errors.NewValidationError("bucket_size", "must be positive")
+	}
+	buckets := make(map[int64]*VolumeBucket)
+	for _, t := range trades {
+		if !t.Price.IsPos() {
+			return nil, errors.NewValidationError("price", fmt.Sprintf("trade %s price %s is not positive", t.ID, t.Price))
+		}
+		q, _, err := t.Price.QuoRem(bucketSize)
+		if err != nil {
+			return nil, err
+		}
+		i, err := q.Int64()
+		if err != nil {
+			return nil, err
+		}
+		b, ok := buckets[i]
+		if !ok {
+			low := udecimal.MustFromInt64(i, 0).Mul(bucketSize)
+			b = &VolumeBucket{PriceLow: low, PriceHigh: low.Add(bucketSize)}
+			buckets[i] = b
+		}
+		if t.AggressorSide() == SideBuy {
+			b.BuyVolume = b.BuyVolume.Add(t.Qty)
+		} else {
+			b.SellVolume = b.SellVolume.Add(t.Qty)
+		}
+	}
+
+	profile := make([]VolumeBucket, 0, len(buckets))
+	for _, b := range buckets {
+		profile = append(profile, *b)
+	}
+	slices.SortFunc(profile, func(a, b VolumeBucket) int { return a.PriceLow.Cmp(b.PriceLow) })
+	return profile, nil
+}
+
+// PointOfControl returns the bucket with the highest volume, the lowest-priced
+// one on a tie. It returns false if profile is empty.
+func PointOfControl(profile []VolumeBucket) (VolumeBucket, bool) {
+	if len(profile) == 0 {
+		return VolumeBucket{}, false
+	}
+	poc := profile[0]
+	for _, b := range profile[1:] {
+		if b.Volume().GreaterThan(poc.Volume()) {
+			poc = b
+		}
+	}
+	return poc, true
+}
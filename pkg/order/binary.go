@@ -0,0 +1,117 @@
+package order
+
+import (
+	"github.com/pwnholic/clara/internal/codec"
+	"github.com/pwnholic/clara/pkg/market"
+)
+
+// orderBinaryVersion is the Order binary layout version. Version 2 added
+// WorkingType, GoodTillDate and Fills; version 1 data still decodes, with
+// those fields zero.
+const orderBinaryVersion = 2
+
+// minFillSize is the smallest encoded Fill: four empty strings, a side,
+// three 11-byte decimals and a bool.
+const minFillSize = 40
+
+// MarshalBinary implements encoding.BinaryMarshaler with a compact format
+// for storage. JSON remains the wire format.
+func (o Order) MarshalBinary() ([]byte, error) {
+	e := codec.NewEncoder(192)
+	e.Version(orderBinaryVersion)
+	e.String(o.ID)
+	e.String(o.ClientID)
+	e.String(o.ReplacesOrderID)
+	e.String(string(o.Symbol))
+	e.Int(int64(o.Side))
+	e.Int(int64(o.Type))
+	e.Int(int64(o.Status))
+	e.Decimal(o.Price)
+	e.Decimal(o.Quantity)
+	e.Decimal(o.ExecutedQty)
+	e.Decimal(o.AvgPrice)
+	e.Decimal(o.StopPrice)
+	e.Int(int64(o.WorkingType))
+	e.Decimal(o.IcebergQty)
+	e.Int(int64(o.TimeInForce))
+	e.Time(o.GoodTillDate)
+	e.Bool(o.ReduceOnly)
+	e.Uint(uint64(len(o.Fills)))
+	for _, f := range o.Fills {
+		e.String(f.ID)
+		e.String(f.OrderID)
+		e.String(string(f.Symbol))
+		e.Int(int64(f.Side))
+		e.Decimal(f.Price)
+		e.Decimal(f.Qty)
+		e.Decimal(f.Commission)
+		e.String(f.CommissionAsset)
+		e.Bool(f.IsMaker)
+		e.Time(f.Timestamp)
+	}
+	e.Time(o.CreatedAt)
+	e.Time(o.UpdatedAt)
+	return e.Bytes()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (o *Order) UnmarshalBinary(data []byte) error {
+	d := codec.NewDecoder(data)
+	ver := d.VersionUpTo(orderBinaryVersion)
+	v := Order{
+		ID:              d.String(),
+		ClientID:        d.String(),
+		ReplacesOrderID: d.String(),
+		Symbol:          market.Symbol(d.String()),
+		Side:            market.Side(d.Int()),
+		Type:            Type(d.Int()),
+		Status:          Status(d.Int()),
+		Price:           d.Decimal(),
+		Quantity:        d.Decimal(),
+		ExecutedQty:     d.Decimal(),
+		AvgPrice:        d.Decimal(),
+		StopPrice:       d.Decimal(),
+	}
+	if ver >= 2 {
+		v.WorkingType = WorkingType(d.Int())
+	}
+	v.IcebergQty = d.Decimal()
+	v.TimeInForce = TimeInForce(d.Int())
+	if ver >= 2 {
+		v.GoodTillDate = d.Time()
+	}
+	v.ReduceOnly = d.Bool()
+	if ver >= 2 {
+		v.Fills = decodeFills(d)
+	}
+	v.CreatedAt = d.Time()
+	v.UpdatedAt = d.Time()
+	if err := d.Err(); err != nil {
+		return err
+	}
+	*o = v
+	return nil
+}
+
+func decodeFills(d *codec.Decoder) []Fill {
+	n := d.Count(minFillSize)
+	if n == 0 {
+		return nil
+	}
+	fills := make([]Fill, n)
+	for i := range fills {
+		fills[i] = Fill{
+			ID:              d.String(),
+			OrderID:         d.String(),
+			Symbol:          market.Symbol(d.String()),
+			Side:            market.Side(d.Int()),
+			Price:           d.Decimal(),
+			Qty:             d.Decimal(),
+			Commission:      d.Decimal(),
+			CommissionAsset: d.String(),
+			IsMaker:         d.Bool(),
+			Timestamp:       d.Time(),
+		}
+	}
+	return fills
+}
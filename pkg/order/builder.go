@@ -0,0 +1,170 @@
+package order
+
+import (
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// Builder constructs an internally consistent Request.
+// Errors from chained calls are deferred and reported by Build.
+//
+// Example:
+//
+//	req, err := order.NewLimitSell(symbol, qty, price).PostOnly().ReduceOnly().Build()
+type Builder struct {
+	req Request
+	err error
+}
+
+func newBuilder(symbol market.Symbol, side market.Side, typ Type, qty udecimal.Decimal) *Builder {
+	return &Builder{
+		req: Request{
+			Symbol:      symbol,
+			Side:        side,
+			Type:        typ,
+			Quantity:    qty,
+			TimeInForce: GTC,
+		},
+	}
+}
+
+// NewMarketBuy starts a market buy order.
+func NewMarketBuy(symbol market.Symbol, qty udecimal.Decimal) *Builder {
+	return newBuilder(symbol, market.SideBuy, TypeMarket, qty)
+}
+
+// NewMarketSell starts a market sell order.
+func NewMarketSell(symbol market.Symbol, qty udecimal.Decimal) *Builder {
+	return newBuilder(symbol, market.SideSell, TypeMarket, qty)
+}
+
+// NewLimitBuy starts a GTC limit buy order.
+func NewLimitBuy(symbol market.Symbol, qty, price udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, market.SideBuy, TypeLimit, qty)
+	b.req.Price = price
+	return b
+}
+
+// NewLimitSell starts a GTC limit sell order.
+func NewLimitSell(symbol market.Symbol, qty, price udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, market.SideSell, TypeLimit, qty)
+	b.req.Price = price
+	return b
+}
+
+// NewStopMarket starts a stop-loss order that executes at market once stopPrice is reached.
+func NewStopMarket(symbol market.Symbol, side market.Side, qty, stopPrice udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, side, TypeStopLoss, qty)
+	b.req.StopPrice = stopPrice
+	return b
+}
+
+// NewStopLimit starts a stop-loss limit order placed at price once stopPrice is reached.
+func NewStopLimit(symbol market.Symbol, side market.Side, qty, price, stopPrice udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, side, TypeStopLossLimit, qty)
+	b.req.Price = price
+	b.req.StopPrice = stopPrice
+	return b
+}
+
+// NewTakeProfitMarket starts a take-profit order that executes at market once stopPrice is reached.
+func NewTakeProfitMarket(symbol market.Symbol, side market.Side, qty, stopPrice udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, side, TypeTakeProfit, qty)
+	b.req.StopPrice = stopPrice
+	return b
+}
+
+// NewTakeProfitLimit starts a take-profit limit order placed at price once stopPrice is reached.
+func NewTakeProfitLimit(symbol market.Symbol, side market.Side, qty, price, stopPrice udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, side, TypeTakeProfitLimit, qty)
+	b.req.Price = price
+	b.req.StopPrice = stopPrice
+	return b
+}
+
+// NewTrailingStop starts a trailing stop that executes at market once price
+// reverses by callbackRate percent from its best level. See ActivationPrice.
+func NewTrailingStop(symbol market.Symbol, side market.Side, qty, callbackRate udecimal.Decimal) *Builder {
+	b := newBuilder(symbol, side, TypeTrailingStop, qty)
+	b.req.CallbackRate = callbackRate
+	return b
+}
+
+// ActivationPrice sets the price a trailing stop starts trailing from; by
+// default it trails from placement. Only trailing stops accept it.
+func (b *Builder) ActivationPrice(price udecimal.Decimal) *Builder {
+	if b.req.Type != TypeTrailingStop {
+		b.setErr(errors.NewValidationError("stop_price", "activation price is only valid for trailing stops"))
+		return b
+	}
+	b.req.StopPrice = price
+	return b
+}
+
+// TimeInForce sets the time in force. Only limit-type orders accept a value other than GTC.
+func (b *Builder) TimeInForce(tif TimeInForce) *Builder {
+	if !b.req.Type.IsLimit() && tif != GTC {
+		b.setErr(errors.NewValidationError("time_in_force", "only limit orders accept a time in force"))
+		return b
+	}
+	b.req.TimeInForce = tif
+	return b
+}
+
+// PostOnly makes the order maker-only (GTX).
+func (b *Builder) PostOnly() *Builder {
+	return b.TimeInForce(GTX)
+}
+
+// IOC makes the order immediate-or-cancel.
+func (b *Builder) IOC() *Builder {
+	return b.TimeInForce(IOC)
+}
+
+// FOK makes the order fill-or-kill.
+func (b *Builder) FOK() *Builder {
+	return b.TimeInForce(FOK)
+}
+
+// WorkingType sets the price the stop price triggers on. Only trigger orders accept it.
+func (b *Builder) WorkingType(w WorkingType) *Builder {
+	if !b.req.Type.IsTrigger() && w != WorkingTypeContract {
+		b.setErr(errors.NewValidationError("working_type", "working type is only valid for trigger orders"))
+		return b
+	}
+	b.req.WorkingType = w
+	return b
+}
+
+// ReduceOnly marks the order as reduce-only.
+func (b *Builder) ReduceOnly() *Builder {
+	b.req.ReduceOnly = true
+	return b
+}
+
+// ClientID sets the client order ID.
+func (b *Builder) ClientID(id string) *Builder {
+	b.req.ClientID = id
+	return b
+}
+
+// Build validates and returns the request.
+// Each call returns a new Request, so a Builder can be reused as a template.
+func (b *Builder) Build() (*Request, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	req := b.req
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// setErr records the first error encountered while chaining.
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
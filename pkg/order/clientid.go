@@ -0,0 +1,91 @@
+package order
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+)
+
+// MaxClientIDLen is the longest client order ID every supported exchange
+// accepts (Binance newClientOrderId, Bybit orderLinkId).
+const MaxClientIDLen = 36
+
+const (
+	base62Digits    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	clientIDTimeLen = 7  // Base62 Unix milliseconds, enough until 2081
+	clientIDRandLen = 10 // Base62 random suffix, about 59 bits
+)
+
+// NewClientID returns a client order ID made of prefix, a base62 timestamp
+// and a random base62 suffix. Characters of prefix outside [A-Za-z0-9_-] are
+// dropped and it is truncated to keep the ID within MaxClientIDLen, so the
+// result always passes Request.Validate. IDs sort by creation time within a
+// prefix.
+func NewClientID(prefix string) string {
+	return NewClientIDAt(prefix, time.Now())
+}
+
+// NewClientIDAt is NewClientID with the timestamp taken from now, for callers
+// running on a simulated clock.
+func NewClientIDAt(prefix string, now time.Time) string {
+	prefix = strings.Map(func(r rune) rune {
+		if isClientIDChar(r) {
+			return r
+		}
+		return -1
+	}, prefix)
+	prefix = prefix[:min(len(prefix), MaxClientIDLen-clientIDTimeLen-clientIDRandLen)]
+
+	var b strings.Builder
+	b.Grow(len(prefix) + clientIDTimeLen + clientIDRandLen)
+	b.WriteString(prefix)
+	b.WriteString(base62(uint64(now.UnixMilli()), clientIDTimeLen))
+	b.WriteString(randBase62(clientIDRandLen))
+	return b.String()
+}
+
+// validateClientID checks id against the length and charset common to all
+// supported exchanges.
+func validateClientID(id string) error {
+	if len(id) > MaxClientIDLen {
+		return errors.NewValidationError("client_id", fmt.Sprintf("must be at most %d characters", MaxClientIDLen))
+	}
+	for _, r := range id {
+		if !isClientIDChar(r) {
+			return errors.NewValidationError("client_id", fmt.Sprintf("invalid character %q, allowed are letters, digits, '_' and '-'", r))
+		}
+	}
+	return nil
+}
+
+func isClientIDChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-'
+}
+
+// base62 formats v in base62, left-padded with zeros to width.
+func base62(v uint64, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = base62Digits[v%62]
+		v /= 62
+	}
+	return string(buf)
+}
+
+// randBase62 returns n random base62 characters.
+func randBase62(n int) string {
+	buf := make([]byte, n)
+	limit := big.NewInt(62)
+	for i := range buf {
+		d, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			panic(err) // crypto/rand never fails on supported platforms
+		}
+		buf[i] = base62Digits[d.Int64()]
+	}
+	return string(buf)
+}
@@ -0,0 +1,32 @@
+package order
+
+import (
+	"time"
+
+	"github.com/quagmt/udecimal"
+)
+
+// ConvertQuote is a normalized instant-convert (OTC) quote.
+type ConvertQuote struct {
+	ID          string           `json:"id"`
+	FromAsset   string           `json:"from_asset"`
+	ToAsset     string           `json:"to_asset"`
+	FromAmount  udecimal.Decimal `json:"from_amount"`  // Amount of FromAsset spent
+	ToAmount    udecimal.Decimal `json:"to_amount"`    // Amount of ToAsset received
+	Rate        udecimal.Decimal `json:"rate"`         // ToAsset received per unit of FromAsset
+	InverseRate udecimal.Decimal `json:"inverse_rate"` // FromAsset spent per unit of ToAsset
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// IsExpired returns true if the quote can no longer be accepted at now.
+func (q ConvertQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// TTL returns the time left before the quote expires, or zero if it has expired.
+func (q ConvertQuote) TTL(now time.Time) time.Duration {
+	if q.IsExpired(now) {
+		return 0
+	}
+	return q.ExpiresAt.Sub(now)
+}
@@ -0,0 +1,271 @@
+package exec
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
+)
+
+// BracketPollInterval is how often Bracket looks its orders up in case the
+// order stream dropped an update.
+const BracketPollInterval = 5 * time.Second
+
+// BracketClient places, replaces, cancels and looks up orders and streams
+// their updates. exchange.Client satisfies it.
+type BracketClient interface {
+	Client
+	CancelOrder(ctx context.Context, req *order.CancelRequest) error
+	CancelReplace(ctx context.Context, cancel *order.CancelRequest, place *order.Request) (*order.Order, error)
+	GetOrderByClientID(ctx context.Context, symbol market.Symbol, clientID string) (*order.Order, error)
+	OrderStream(opts ...stream.Option) stream.Stream[order.Order]
+}
+
+// Bracket places entry and protects what it fills with a stop and a
+// take-profit leg, either of which may be nil. It blocks until the bracket
+// is resolved.
+//
+// The legs are placed once the entry first fills and are replaced whenever it
+// fills more, so they always cover the executed quantity; their own
+// Quantity is ignored. They must be for the entry's symbol on the opposite
+// side. When a leg fills the other is cancelled, along with what is left of
+// the entry, and Bracket returns; with a single leg it also returns once the
+// entry is done and the leg covers all of it.
+//
+// If the entry ends without any fill, Bracket returns an error wrapping
+// errors.ErrOrderNotActive. So it does if a leg ends without filling
+// completely (rejected, expired or cancelled from elsewhere, possibly after a
+// partial fill): the rest of the entry is cancelled but the other leg is left
+// working, since the position is not closed. If ctx is cancelled, the entry
+// is cancelled if still open, legs already placed are left working to
+// protect the position, and ctx.Err() is returned; the same happens, with
+// the error, if a leg cannot be placed. The legs are not resized for each
+// other's partial fills; make them ReduceOnly where the exchange supports it.
+//
+// Orders without a ClientID get one: the entry from order.NewClientID and the
+// legs from the entry's, which is how updates are matched. Updates come from
+// client.OrderStream, and every BracketPollInterval the open orders are also
+// looked up with GetOrderByClientID.
+func Bracket(ctx context.Context, client BracketClient, entry *order.Request, stop, tp *order.Request) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	b := &bracket{client: client, symbol: entry.Symbol, tracked: make(map[string]*order.Order)}
+	for _, l := range []struct {
+		req    *order.Request
+		suffix string
+	}{{stop, "sl"}, {tp, "tp"}} {
+		if l.req == nil {
+			continue
+		}
+		if err := validateLeg(entry, l.req); err != nil {
+			return err
+		}
+		b.legs = append(b.legs, &bracketLeg{template: *l.req, suffix: l.suffix})
+	}
+	if len(b.legs) == 0 {
+		return errors.NewValidationError("stop", "stop or take-profit leg is required")
+	}
+
+	req := *entry
+	if req.ClientID == "" {
+		req.ClientID = order.NewClientID("bkt")
+	}
+	b.entryID = req.ClientID
+
+	updates := client.OrderStream()
+	ch, err := updates.Subscribe(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribe order stream: %w", err)
+	}
+	defer func() { _ = updates.Unsubscribe(context.Background()) }()
+
+	placed, err := client.PlaceOrder(ctx, &req)
+	if err != nil {
+		return fmt.Errorf("place bracket entry: %w", err)
+	}
+	b.tracked[b.entryID] = placed
+
+	poll := time.NewTicker(BracketPollInterval)
+	defer poll.Stop()
+	for {
+		done, err := b.step(ctx)
+		if done || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			if err := b.cancelEntry(ctx); err != nil {
+				return fmt.Errorf("%w; %w", ctx.Err(), err)
+			}
+			return ctx.Err()
+		case o, ok := <-ch:
+			if !ok {
+				ch = nil // Rely on polling
+				continue
+			}
+			b.update(o)
+		case <-poll.C:
+			b.poll(ctx)
+		}
+	}
+}
+
+// validateLeg checks a protective leg against the entry it protects.
+func validateLeg(entry, leg *order.Request) error {
+	if leg.Symbol != entry.Symbol {
+		return errors.NewValidationError("symbol", fmt.Sprintf("leg symbol %s differs from entry %s", leg.Symbol, entry.Symbol))
+	}
+	if leg.Side == entry.Side {
+		return errors.NewValidationError("side", "leg must be on the opposite side of the entry")
+	}
+	check := *leg
+	check.Quantity = entry.Quantity
+	return check.Validate()
+}
+
+// bracket is the state of one Bracket call.
+type bracket struct {
+	client  BracketClient
+	symbol  market.Symbol
+	entryID string
+	legs    []*bracketLeg
+	tracked map[string]*order.Order // Latest state of the entry and current legs, by ClientID
+}
+
+// bracketLeg is a protective leg, replaced as the entry fills.
+type bracketLeg struct {
+	template order.Request
+	suffix   string
+	gen      int
+	clientID string           // ClientID of the working order; empty until placed
+	closed   udecimal.Decimal // Executed by replaced orders
+}
+
+// step acts on the latest state: it places or resizes the legs and reports
+// whether the bracket is resolved.
+func (b *bracket) step(ctx context.Context) (bool, error) {
+	entry := b.tracked[b.entryID]
+	for _, l := range b.legs {
+		cur := b.tracked[l.clientID]
+		if cur == nil || !cur.Status.IsTerminal() {
+			continue
+		}
+		if cur.Status != order.StatusFilled {
+			// Leave the other leg protecting what it can.
+			err := fmt.Errorf("%w: bracket %s leg %s %s with %s of %s filled", errors.ErrOrderNotActive, l.suffix, cur.ID, cur.Status, cur.ExecutedQty, cur.Quantity)
+			return false, stderrors.Join(err, b.cancelEntry(ctx))
+		}
+		err := b.cancelEntry(ctx)
+		for _, other := range b.legs {
+			if other != l {
+				err = stderrors.Join(err, b.cancel(ctx, other.clientID))
+			}
+		}
+		return true, err
+	}
+	if entry.ExecutedQty.IsPos() {
+		for _, l := range b.legs {
+			if err := b.protect(ctx, l, entry.ExecutedQty); err != nil {
+				// Stop growing a position that cannot be protected.
+				return false, stderrors.Join(err, b.cancelEntry(ctx))
+			}
+		}
+	}
+	if !entry.Status.IsTerminal() {
+		return false, nil
+	}
+	if !entry.ExecutedQty.IsPos() {
+		return false, fmt.Errorf("%w: bracket entry %s %s without fills", errors.ErrOrderNotActive, entry.ID, entry.Status)
+	}
+	return len(b.legs) == 1, nil
+}
+
+// protect places l, or replaces it, so that it covers executed.
+func (b *bracket) protect(ctx context.Context, l *bracketLeg, executed udecimal.Decimal) error {
+	cur := b.tracked[l.clientID]
+	if cur != nil && (cur.Status.IsTerminal() || cur.Quantity.Add(l.closed).GreaterThanOrEqual(executed)) {
+		return nil
+	}
+
+	req := l.template
+	req.Quantity = executed.Sub(l.closed)
+	if cur != nil {
+		req.Quantity = req.Quantity.Sub(cur.ExecutedQty)
+	}
+	if !req.Quantity.IsPos() {
+		return nil
+	}
+	l.gen++
+	suffix := fmt.Sprintf("-%s%d", l.suffix, l.gen)
+	req.ClientID = b.entryID[:min(len(b.entryID), order.MaxClientIDLen-len(suffix))] + suffix
+
+	var (
+		o   *order.Order
+		err error
+	)
+	if cur == nil {
+		o, err = b.client.PlaceOrder(ctx, &req)
+	} else {
+		o, err = b.client.CancelReplace(ctx, &order.CancelRequest{Symbol: b.symbol, ClientID: l.clientID}, &req)
+	}
+	if err != nil {
+		return fmt.Errorf("place bracket %s leg: %w", l.suffix, err)
+	}
+	if cur != nil {
+		l.closed = l.closed.Add(cur.ExecutedQty)
+		delete(b.tracked, l.clientID)
+	}
+	l.clientID = req.ClientID
+	b.tracked[l.clientID] = o
+	return nil
+}
+
+// update records o if it is a newer state of a tracked order.
+func (b *bracket) update(o order.Order) {
+	cur, ok := b.tracked[o.ClientID]
+	if !ok || o.Symbol != b.symbol || (cur != nil && o.UpdatedAt.Before(cur.UpdatedAt)) {
+		return
+	}
+	b.tracked[o.ClientID] = &o
+}
+
+// poll looks up the tracked orders that are still open. Failed lookups are
+// retried at the next poll.
+func (b *bracket) poll(ctx context.Context) {
+	for id, cur := range b.tracked {
+		if cur.Status.IsTerminal() {
+			continue
+		}
+		if o, err := b.client.GetOrderByClientID(ctx, b.symbol, id); err == nil {
+			b.update(*o)
+		}
+	}
+}
+
+// cancelEntry cancels the entry if it is still open.
+func (b *bracket) cancelEntry(ctx context.Context) error {
+	if !b.tracked[b.entryID].IsOpen() {
+		return nil
+	}
+	return b.cancel(ctx, b.entryID)
+}
+
+// cancel cancels the order with clientID, if any, even once ctx is done.
+// An order that already finished is not an error.
+func (b *bracket) cancel(ctx context.Context, clientID string) error {
+	if clientID == "" {
+		return nil
+	}
+	err := b.client.CancelOrder(context.WithoutCancel(ctx), &order.CancelRequest{Symbol: b.symbol, ClientID: clientID})
+	if err != nil && !stderrors.Is(err, errors.ErrOrderNotActive) {
+		return fmt.Errorf("cancel bracket order %s: %w", clientID, err)
+	}
+	return nil
+}
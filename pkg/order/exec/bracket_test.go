@@ -0,0 +1,163 @@
+package exec
+
+import (
+	"context"
+	stderrors "errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/pwnholic/clara/pkg/stream"
+	"github.com/quagmt/udecimal"
+)
+
+// fakeOrderStream is an order stream the test feeds with Emit.
+type fakeOrderStream struct {
+	*stream.BaseStream[order.Order]
+}
+
+func (s fakeOrderStream) Subscribe(ctx context.Context) (<-chan order.Order, error) {
+	out := s.DataChannel()
+	err := s.Start(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	return out, err
+}
+
+func (s fakeOrderStream) Unsubscribe(context.Context) error {
+	return s.Stop()
+}
+
+// fakeBracketClient accepts every order as NEW and reports it on placed.
+type fakeBracketClient struct {
+	updates fakeOrderStream
+	placed  chan order.Order
+
+	mu      sync.Mutex
+	cancels []string
+}
+
+func newFakeBracketClient() *fakeBracketClient {
+	return &fakeBracketClient{
+		updates: fakeOrderStream{stream.NewBaseStream[order.Order](stream.DefaultConfig())},
+		placed:  make(chan order.Order, 10),
+	}
+}
+
+func (c *fakeBracketClient) PlaceOrder(_ context.Context, req *order.Request) (*order.Order, error) {
+	o := order.Order{
+		ID:        req.ClientID,
+		ClientID:  req.ClientID,
+		Symbol:    req.Symbol,
+		Side:      req.Side,
+		Type:      req.Type,
+		Quantity:  req.Quantity,
+		Status:    order.StatusNew,
+		UpdatedAt: time.Now(),
+	}
+	c.placed <- o
+	return &o, nil
+}
+
+func (c *fakeBracketClient) CancelOrder(_ context.Context, req *order.CancelRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancels = append(c.cancels, req.ClientID)
+	return nil
+}
+
+func (c *fakeBracketClient) CancelReplace(context.Context, *order.CancelRequest, *order.Request) (*order.Order, error) {
+	return nil, errors.ErrNotSupported
+}
+
+func (c *fakeBracketClient) GetOrderByClientID(context.Context, market.Symbol, string) (*order.Order, error) {
+	return nil, errors.ErrOrderNotFound
+}
+
+func (c *fakeBracketClient) OrderStream(...stream.Option) stream.Stream[order.Order] {
+	return c.updates
+}
+
+func (c *fakeBracketClient) cancelled(clientID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.cancels, clientID)
+}
+
+// runBracket starts a bracket of a 1 BTC market buy, fills the entry, and
+// ends the take-profit leg with status after executing executed of it.
+func runBracket(t *testing.T, status order.Status, executed string) (*fakeBracketClient, order.Order, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := newFakeBracketClient()
+	entry := &order.Request{Symbol: "BTCUSDT", Side: market.SideBuy, Type: order.TypeMarket, Quantity: udecimal.One, ClientID: "entry"}
+	stop := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeStopLoss, StopPrice: udecimal.MustParse("90"), Quantity: udecimal.One}
+	tp := &order.Request{Symbol: "BTCUSDT", Side: market.SideSell, Type: order.TypeLimit, Price: udecimal.MustParse("110"), Quantity: udecimal.One}
+	done := make(chan error, 1)
+	go func() { done <- Bracket(ctx, c, entry, stop, tp) }()
+
+	next := func() order.Order {
+		select {
+		case o := <-c.placed:
+			return o
+		case <-ctx.Done():
+			t.Fatal("order not placed")
+			return order.Order{}
+		}
+	}
+	filled := next()
+	filled.Status, filled.ExecutedQty, filled.UpdatedAt = order.StatusFilled, filled.Quantity, time.Now()
+	c.updates.Emit(filled)
+
+	sl, tpLeg := next(), next()
+	tpLeg.Status, tpLeg.ExecutedQty, tpLeg.UpdatedAt = status, udecimal.MustParse(executed), time.Now()
+	c.updates.Emit(tpLeg)
+
+	select {
+	case err := <-done:
+		return c, sl, err
+	case <-ctx.Done():
+		t.Fatal("Bracket did not return")
+		return nil, order.Order{}, nil
+	}
+}
+
+func TestBracketLegFilled(t *testing.T) {
+	c, sl, err := runBracket(t, order.StatusFilled, "1")
+	if err != nil {
+		t.Fatalf("Bracket: %v", err)
+	}
+	if !c.cancelled(sl.ClientID) {
+		t.Fatal("stop leg not cancelled after the take-profit filled")
+	}
+}
+
+// TestBracketLegPartiallyFilledThenCancelled checks that a leg cancelled
+// after a partial fill does not resolve the bracket: the remaining position
+// is still open, so the other leg must stay working.
+func TestBracketLegPartiallyFilledThenCancelled(t *testing.T) {
+	c, sl, err := runBracket(t, order.StatusCancelled, "0.4")
+	if !stderrors.Is(err, errors.ErrOrderNotActive) {
+		t.Fatalf("err = %v, want ErrOrderNotActive", err)
+	}
+	if c.cancelled(sl.ClientID) {
+		t.Fatal("stop leg cancelled although 0.6 of the position is still open")
+	}
+}
+
+func TestBracketLegRejected(t *testing.T) {
+	c, sl, err := runBracket(t, order.StatusRejected, "0")
+	if !stderrors.Is(err, errors.ErrOrderNotActive) {
+		t.Fatalf("err = %v, want ErrOrderNotActive", err)
+	}
+	if c.cancelled(sl.ClientID) {
+		t.Fatal("stop leg cancelled after the take-profit was rejected")
+	}
+}
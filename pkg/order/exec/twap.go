@@ -0,0 +1,127 @@
+// Package exec provides execution algorithms that work a parent order as a
+// series of child orders.
+package exec
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+// Client places orders. exchange.Client satisfies it.
+type Client interface {
+	PlaceOrder(ctx context.Context, req *order.Request) (*order.Order, error)
+}
+
+// TWAPClient places and looks up orders and the trading rules they must
+// follow. exchange.Client satisfies it.
+type TWAPClient interface {
+	Client
+	GetSymbolInfo(ctx context.Context, symbol market.Symbol) (*market.SymbolInfo, error)
+	GetOrderByClientID(ctx context.Context, symbol market.Symbol, clientID string) (*order.Order, error)
+}
+
+// TWAP works req as slices child orders of equal quantity, placing the first
+// immediately and one more every interval.
+//
+// Slice quantities are rounded down to the symbol's step size (see
+// market.SymbolInfo.RoundQty) and the last slice takes the remainder, rounded
+// the same way, so the children sum to the parent quantity when it is itself
+// a multiple of the step.
+// Children inherit every field of req, including ReduceOnly. Child i is
+// tagged "<ClientID>-<i>" (1-based), with ClientID truncated to keep the tag
+// within order.MaxClientIDLen; without a ClientID, req gets one from
+// order.NewClientID.
+//
+// After a retryable error (see errors.IsRetryable) the exchange may still
+// have accepted the slice, so it is looked up by its ClientID: if found it
+// counts as placed, and if the lookup returns errors.ErrOrderNotFound its
+// quantity is carried over to the next slice. Any other error, including a
+// failed lookup, a retryable error on the last slice, or ctx being cancelled
+// aborts the TWAP; the orders placed so far are returned along with the
+// error.
+func TWAP(ctx context.Context, client TWAPClient, req *order.Request, slices int, interval time.Duration) ([]order.Order, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if slices < 1 {
+		return nil, errors.NewValidationError("slices", "must be at least 1")
+	}
+	if interval < 0 {
+		return nil, errors.NewValidationError("interval", "must be non-negative")
+	}
+
+	info, err := client.GetSymbolInfo(ctx, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("get symbol info: %w", err)
+	}
+	size, err := req.Quantity.Div64(uint64(slices))
+	if err != nil {
+		return nil, fmt.Errorf("calculate slice size: %w", err)
+	}
+	size = info.RoundQty(size)
+	if !size.IsPos() {
+		return nil, errors.NewValidationError("slices", "slice quantity rounds to zero")
+	}
+
+	parentID := req.ClientID
+	if parentID == "" {
+		parentID = order.NewClientID("twap")
+	}
+
+	var (
+		placed    []order.Order
+		carry     udecimal.Decimal
+		remaining = req.Quantity
+	)
+	ticker := time.NewTicker(max(interval, time.Nanosecond))
+	defer ticker.Stop()
+
+	for i := range slices {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return placed, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+
+		qty := size.Add(carry)
+		if i == slices-1 {
+			qty = info.RoundQty(remaining)
+		}
+
+		child := *req
+		child.Quantity = qty
+		suffix := fmt.Sprintf("-%d", i+1)
+		child.ClientID = parentID[:min(len(parentID), order.MaxClientIDLen-len(suffix))] + suffix
+
+		o, err := client.PlaceOrder(ctx, &child)
+		if err != nil && errors.IsRetryable(err) {
+			var lookupErr error
+			o, lookupErr = client.GetOrderByClientID(context.WithoutCancel(ctx), req.Symbol, child.ClientID)
+			switch {
+			case lookupErr == nil:
+				err = nil
+			case stderrors.Is(lookupErr, errors.ErrOrderNotFound) && i < slices-1 && ctx.Err() == nil:
+				carry = qty
+				continue
+			case !stderrors.Is(lookupErr, errors.ErrOrderNotFound):
+				err = fmt.Errorf("%w; look up slice %s: %w", err, child.ClientID, lookupErr)
+			}
+		}
+		if err != nil {
+			return placed, fmt.Errorf("twap slice %d/%d: %w", i+1, slices, err)
+		}
+		placed = append(placed, *o)
+		carry = udecimal.Decimal{}
+		remaining = remaining.Sub(qty)
+	}
+	return placed, nil
+}
@@ -0,0 +1,60 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// Fill is an execution of one of the account's own orders, as opposed to a
+// public market.Trade. Fills are the ground truth for fees and realized PnL.
+type Fill struct {
+	ID              string           `json:"id"` // Exchange trade ID
+	OrderID         string           `json:"order_id"`
+	Symbol          market.Symbol    `json:"symbol"`
+	Side            market.Side      `json:"side"`
+	Price           udecimal.Decimal `json:"price"`
+	Qty             udecimal.Decimal `json:"qty"`
+	Commission      udecimal.Decimal `json:"commission"`
+	CommissionAsset string           `json:"commission_asset"`
+	IsMaker         bool             `json:"is_maker"`
+	Timestamp       time.Time        `json:"timestamp"`
+}
+
+// Value returns the fill value in the quote asset (Price * Qty).
+func (f Fill) Value() udecimal.Decimal {
+	return f.Price.Mul(f.Qty)
+}
+
+// avgPriceTolerance is the relative difference between AvgPrice and the fills
+// that ReconcileAvgPrice accepts, covering exchanges rounding AvgPrice (1 bps).
+var avgPriceTolerance = udecimal.MustFromInt64(1, 4)
+
+// ComputeAvgPrice returns the quantity-weighted average price of o.Fills.
+func (o Order) ComputeAvgPrice() (udecimal.Decimal, error) {
+	var notional, qty udecimal.Decimal
+	for _, f := range o.Fills {
+		notional = notional.Add(f.Value())
+		qty = qty.Add(f.Qty)
+	}
+	if qty.IsZero() {
+		return udecimal.Decimal{}, errors.NewValidationError("fills", "order has no fills")
+	}
+	return notional.Div(qty)
+}
+
+// ReconcileAvgPrice checks AvgPrice against ComputeAvgPrice and returns an
+// error if they differ by more than 1 bps of the computed price.
+func (o Order) ReconcileAvgPrice() error {
+	computed, err := o.ComputeAvgPrice()
+	if err != nil {
+		return err
+	}
+	if o.AvgPrice.Sub(computed).Abs().GreaterThan(computed.Mul(avgPriceTolerance)) {
+		return errors.NewValidationError("avg_price", fmt.Sprintf("avg price %s differs from fills average %s", o.AvgPrice, computed))
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package order
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/quagmt/udecimal"
+)
+
+// GridSpacing selects how grid levels are distributed between the bounds.
+type GridSpacing int
+
+const (
+	GridArithmetic GridSpacing = iota // Constant price difference between levels
+	GridGeometric                     // Constant price ratio between levels
+)
+
+// String implements fmt.Stringer.
+func (s GridSpacing) String() string {
+	switch s {
+	case GridArithmetic:
+		return "arithmetic"
+	case GridGeometric:
+		return "geometric"
+	default:
+		return "unknown"
+	}
+}
+
+// GridParams configures GenerateGrid.
+type GridParams struct {
+	Symbol       market.Symbol
+	Lower        udecimal.Decimal // Price of the lowest level
+	Upper        udecimal.Decimal // Price of the highest level
+	Levels       int              // Number of levels including both bounds (>= 2)
+	Quantity     udecimal.Decimal // Quantity per level
+	CurrentPrice udecimal.Decimal // Levels below are buys, above are sells
+	Spacing      GridSpacing
+
+	// Info aligns prices to the tick size and quantities to the step size.
+	// Optional; when nil, prices and quantities are used as computed.
+	Info *market.SymbolInfo
+}
+
+// Validate validates the grid parameters.
+func (p GridParams) Validate() error {
+	if !p.Symbol.IsValid() {
+		return errors.NewValidationError("symbol", "symbol is required")
+	}
+	if !p.Lower.IsPos() {
+		return errors.NewValidationError("lower", "must be positive")
+	}
+	if !p.Upper.GreaterThan(p.Lower) {
+		return errors.NewValidationError("upper", "must be greater than lower")
+	}
+	if p.Levels < 2 {
+		return errors.NewValidationError("levels", "must be at least 2")
+	}
+	if !p.Quantity.IsPos() {
+		return errors.NewValidationError("quantity", "must be positive")
+	}
+	if !p.CurrentPrice.IsPos() {
+		return errors.NewValidationError("current_price", "must be positive")
+	}
+	if p.Spacing != GridArithmetic && p.Spacing != GridGeometric {
+		return errors.NewValidationError("spacing", fmt.Sprintf("unknown spacing: %d", p.Spacing))
+	}
+	return nil
+}
+
+// GenerateGrid returns GTC limit orders spaced between Lower and Upper, sorted by
+// ascending price. Levels priced below CurrentPrice are buys and levels above are
+// sells; a level landing exactly on CurrentPrice (after tick alignment) is skipped
+// so no order crosses the market. Levels that collapse onto the same tick are
+// emitted once.
+func GenerateGrid(params GridParams) ([]*Request, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	prices, err := gridPrices(params)
+	if err != nil {
+		return nil, err
+	}
+
+	qty := params.Quantity
+	if params.Info != nil {
+		qty = params.Info.RoundQty(qty)
+		if !qty.IsPos() {
+			return nil, errors.NewValidationError("quantity", "rounds to zero at the symbol step size")
+		}
+	}
+
+	reqs := make([]*Request, 0, len(prices))
+	var last udecimal.Decimal
+	for i, price := range prices {
+		if params.Info != nil {
+			price = params.Info.RoundPrice(price)
+		}
+		if !price.IsPos() || (i > 0 && price.Equal(last)) {
+			continue
+		}
+		last = price
+
+		var side market.Side
+		switch price.Cmp(params.CurrentPrice) {
+		case -1:
+			side = market.SideBuy
+		case 1:
+			side = market.SideSell
+		default:
+			continue
+		}
+
+		req := &Request{
+			Symbol:      params.Symbol,
+			Side:        side,
+			Type:        TypeLimit,
+			Quantity:    qty,
+			Price:       price,
+			TimeInForce: GTC,
+		}
+		if err := req.Validate(); err != nil {
+			return nil, fmt.Errorf("grid level %d: %w", i, err)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// gridPrices returns the unrounded level prices from Lower to Upper inclusive.
+func gridPrices(p GridParams) ([]udecimal.Decimal, error) {
+	n := p.Levels - 1
+	prices := make([]udecimal.Decimal, p.Levels)
+	prices[0] = p.Lower
+	prices[n] = p.Upper
+
+	switch p.Spacing {
+	case GridArithmetic:
+		step, err := p.Upper.Sub(p.Lower).Div64(uint64(n))
+		if err != nil {
+			return nil, fmt.Errorf("calculate grid step: %w", err)
+		}
+		for i := 1; i < n; i++ {
+			prices[i] = prices[i-1].Add(step)
+		}
+	case GridGeometric:
+		span, err := p.Upper.Div(p.Lower)
+		if err != nil {
+			return nil, fmt.Errorf("calculate grid span: %w", err)
+		}
+		ratio, err := nthRoot(span, n)
+		if err != nil {
+			return nil, fmt.Errorf("calculate grid ratio: %w", err)
+		}
+		for i := 1; i < n; i++ {
+			prices[i] = prices[i-1].Mul(ratio)
+		}
+	}
+	return prices, nil
+}
+
+// nthRoot returns the n-th root of a (a > 0). A float64 estimate is refined with
+// Newton iterations in decimal arithmetic, so the result is deterministic.
+func nthRoot(a udecimal.Decimal, n int) (udecimal.Decimal, error) {
+	if n == 1 {
+		return a, nil
+	}
+	x, err := udecimal.NewFromFloat64(math.Pow(a.InexactFloat64(), 1/float64(n)))
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+	for range 3 {
+		// x = ((n-1)*x + a/x^(n-1)) / n
+		q, err := a.Div(x.PowInt(n - 1))
+		if err != nil {
+			return udecimal.Decimal{}, err
+		}
+		x, err = x.Mul64(uint64(n - 1)).Add(q).Div64(uint64(n))
+		if err != nil {
+			return udecimal.Decimal{}, err
+		}
+	}
+	return x, nil
+}
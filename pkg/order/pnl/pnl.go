@@ -0,0 +1,138 @@
+// Package pnl computes realized profit and loss from an account's fills.
+package pnl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pwnholic/clara/pkg/errors"
+	"github.com/pwnholic/clara/pkg/market"
+	"github.com/pwnholic/clara/pkg/order"
+	"github.com/quagmt/udecimal"
+)
+
+// Lot is inventory opened by a fill and not yet closed.
+type Lot struct {
+	Side      market.Side      // SideBuy for a long lot, SideSell for a short one
+	Qty       udecimal.Decimal // Open quantity, always positive
+	Price     udecimal.Decimal // Entry price
+	Timestamp time.Time        // Time of the opening fill
+}
+
+// Result is the outcome of matching a sequence of fills.
+type Result struct {
+	Gross udecimal.Decimal // Realized PnL before commissions, in the quote asset
+	Fees  udecimal.Decimal // Commissions of every fill, in the quote asset
+	Net   udecimal.Decimal // Gross - Fees
+	Open  []Lot            // Remaining inventory, oldest first; all lots share a side
+}
+
+// OpenQty returns the remaining position: positive when long, negative when
+// short, zero when flat.
+func (r Result) OpenQty() udecimal.Decimal {
+	var qty udecimal.Decimal
+	for _, l := range r.Open {
+		if l.Side == market.SideSell {
+			qty = qty.Sub(l.Qty)
+		} else {
+			qty = qty.Add(l.Qty)
+		}
+	}
+	return qty
+}
+
+// Realized returns the realized PnL net of commissions and the commissions
+// paid, both in quoteAsset, matching fills first in, first out. The gross PnL
+// is pnl + fees; use Compute for it and for the inventory left open.
+func Realized(fills []order.Fill, quoteAsset string) (pnl, fees udecimal.Decimal, err error) {
+	r, err := Compute(fills, quoteAsset)
+	if err != nil {
+		return udecimal.Decimal{}, udecimal.Decimal{}, err
+	}
+	return r.Net, r.Fees, nil
+}
+
+// Compute matches fills first in, first out and returns the realized PnL and
+// the open inventory. Fills are processed in Timestamp order, ties keeping
+// their order in the slice, and must all be for one symbol.
+//
+// A fill against the opposite side closes the oldest lots first, realizing
+// (exit - entry) * qty for longs and (entry - exit) * qty for shorts. A fill
+// larger than the open position closes it and opens a lot on its own side
+// with the remainder, so a position can flip from long to short.
+//
+// Commissions in quoteAsset are counted as is and commissions in the base
+// asset are converted at the fill price; quantities are not reduced by base
+// commissions. Any other commission asset (e.g. BNB) cannot be priced and is
+// an error.
+func Compute(fills []order.Fill, quoteAsset string) (Result, error) {
+	if quoteAsset == "" {
+		return Result{}, errors.NewValidationError("quote_asset", "quote asset is required")
+	}
+	sorted := slices.Clone(fills)
+	slices.SortStableFunc(sorted, func(a, b order.Fill) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	var r Result
+	for _, f := range sorted {
+		if err := validateFill(f, sorted[0].Symbol); err != nil {
+			return Result{}, err
+		}
+		fee, err := commission(f, quoteAsset)
+		if err != nil {
+			return Result{}, err
+		}
+		r.Fees = r.Fees.Add(fee)
+
+		remaining := f.Qty
+		for len(r.Open) > 0 && r.Open[0].Side != f.Side && remaining.IsPos() {
+			lot := &r.Open[0]
+			matched := udecimal.Min(lot.Qty, remaining)
+			diff := f.Price.Sub(lot.Price)
+			if lot.Side == market.SideSell {
+				diff = diff.Neg()
+			}
+			r.Gross = r.Gross.Add(diff.Mul(matched))
+			lot.Qty = lot.Qty.Sub(matched)
+			remaining = remaining.Sub(matched)
+			if lot.Qty.IsZero() {
+				r.Open = r.Open[1:]
+			}
+		}
+		if remaining.IsPos() {
+			r.Open = append(r.Open, Lot{Side: f.Side, Qty: remaining, Price: f.Price, Timestamp: f.Timestamp})
+		}
+	}
+	r.Net = r.Gross.Sub(r.Fees)
+	return r, nil
+}
+
+// validateFill checks that f can be matched with fills for symbol.
+func validateFill(f order.Fill, symbol market.Symbol) error {
+	switch {
+	case f.Symbol != symbol:
+		return errors.NewValidationError("symbol", fmt.Sprintf("fill %s is for %s, not %s", f.ID, f.Symbol, symbol))
+	case !f.Qty.IsPos():
+		return errors.NewValidationError("qty", fmt.Sprintf("fill %s: qty must be positive", f.ID))
+	case !f.Price.IsPos():
+		return errors.NewValidationError("price", fmt.Sprintf("fill %s: price must be positive", f.ID))
+	}
+	return nil
+}
+
+// commission returns the commission of f in quoteAsset.
+func commission(f order.Fill, quoteAsset string) (udecimal.Decimal, error) {
+	if f.Commission.IsZero() {
+		return udecimal.Decimal{}, nil
+	}
+	base, ok := strings.CutSuffix(string(f.Symbol), quoteAsset)
+	switch {
+	case strings.EqualFold(f.CommissionAsset, quoteAsset):
+		return f.Commission, nil
+	case ok && base != "" && strings.EqualFold(f.CommissionAsset, base):
+		return f.Commission.Mul(f.Price), nil
+	}
+	return udecimal.Decimal{}, errors.NewValidationError("commission_asset",
+		fmt.Sprintf("fill %s: cannot convert commission in %q to %s", f.ID, f.CommissionAsset, quoteAsset))
+}
@@ -0,0 +1,55 @@
+package order
+
+import (
+	"encoding/json"
+
+	"github.com/pwnholic/clara/pkg/market"
+)
+
+// orderJSON and requestJSON drop the methods of their originals so the
+// fixed-precision fields below can shadow the default decimal fields.
+type (
+	orderJSON   Order
+	requestJSON Request
+)
+
+// MarshalJSONFixed marshals the order with prices and quantities rendered at the
+// symbol's fixed precision (e.g. price "42000.00" for a two-decimal symbol).
+// Use it when the serialized order must be replayed against the exchange.
+func (o Order) MarshalJSONFixed(info market.SymbolInfo) ([]byte, error) {
+	return json.Marshal(struct {
+		orderJSON
+		Price       market.FixedDecimal `json:"price"`
+		Quantity    market.FixedDecimal `json:"quantity"`
+		ExecutedQty market.FixedDecimal `json:"executed_qty"`
+		AvgPrice    market.FixedDecimal `json:"avg_price"`
+		StopPrice   market.FixedDecimal `json:"stop_price,omitempty"`
+		IcebergQty  market.FixedDecimal `json:"iceberg_qty,omitempty"`
+	}{
+		orderJSON:   orderJSON(o),
+		Price:       info.FixedPrice(o.Price),
+		Quantity:    info.FixedQty(o.Quantity),
+		ExecutedQty: info.FixedQty(o.ExecutedQty),
+		AvgPrice:    info.FixedPrice(o.AvgPrice),
+		StopPrice:   info.FixedPrice(o.StopPrice),
+		IcebergQty:  info.FixedQty(o.IcebergQty),
+	})
+}
+
+// MarshalJSONFixed marshals the request with prices and quantities rendered at
+// the symbol's fixed precision.
+func (r Request) MarshalJSONFixed(info market.SymbolInfo) ([]byte, error) {
+	return json.Marshal(struct {
+		requestJSON
+		Quantity   market.FixedDecimal `json:"quantity"`
+		Price      market.FixedDecimal `json:"price,omitempty"`
+		StopPrice  market.FixedDecimal `json:"stop_price,omitempty"`
+		IcebergQty market.FixedDecimal `json:"iceberg_qty,omitempty"`
+	}{
+		requestJSON: requestJSON(r),
+		Quantity:    info.FixedQty(r.Quantity),
+		Price:       info.FixedPrice(r.Price),
+		StopPrice:   info.FixedPrice(r.StopPrice),
+		IcebergQty:  info.FixedQty(r.IcebergQty),
+	})
+}
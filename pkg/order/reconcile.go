@@ -0,0 +1,54 @@
+package order
+
+// Reconcile diffs a local view of open orders against the exchange's, e.g.
+// right after a user data stream reconnects. Orders are matched by ID, or by
+// ClientID when either side has no ID yet (an order placed but not
+// acknowledged locally).
+//
+// added holds remote orders missing locally, removed local orders missing
+// remotely (filled, cancelled or expired while disconnected), and changed the
+// remote version of matched orders whose Status, ExecutedQty, Price or
+// Quantity differ. Each result keeps the order of its input slice.
+func Reconcile(local, remote []Order) (added, removed, changed []Order) {
+	byID := make(map[string]int, len(local))
+	byClientID := make(map[string]int, len(local))
+	for i, o := range local {
+		if o.ID != "" {
+			byID[o.ID] = i
+		}
+		if o.ClientID != "" {
+			byClientID[o.ClientID] = i
+		}
+	}
+
+	matched := make([]bool, len(local))
+	for _, r := range remote {
+		i, ok := byID[r.ID]
+		if !ok || r.ID == "" {
+			i, ok = byClientID[r.ClientID]
+			ok = ok && r.ClientID != "" && (local[i].ID == "" || r.ID == "")
+		}
+		if !ok || matched[i] {
+			added = append(added, r)
+			continue
+		}
+		matched[i] = true
+		if orderChanged(local[i], r) {
+			changed = append(changed, r)
+		}
+	}
+	for i, o := range local {
+		if !matched[i] {
+			removed = append(removed, o)
+		}
+	}
+	return added, removed, changed
+}
+
+// orderChanged reports whether the exchange state of an order moved on.
+func orderChanged(local, remote Order) bool {
+	return local.Status != remote.Status ||
+		!local.ExecutedQty.Equal(remote.ExecutedQty) ||
+		!local.Price.Equal(remote.Price) ||
+		!local.Quantity.Equal(remote.Quantity)
+}
@@ -170,6 +170,7 @@ const (
 	IOC                     // Immediate or Cancel
 	FOK                     // Fill or Kill
 	GTX                     // Good Till Crossing (Post Only)
+	GTD                     // Good Till Date (see Request.GoodTillDate)
 )
 
 // String implements fmt.Stringer.
@@ -183,6 +184,8 @@ func (t TimeInForce) String() string {
 		return "FOK"
 	case GTX:
 		return "GTX"
+	case GTD:
+		return "GTD"
 	default:
 		return "UNKNOWN"
 	}
@@ -204,29 +207,79 @@ func (t *TimeInForce) UnmarshalText(text []byte) error {
 		*t = FOK
 	case "GTX", "POST_ONLY":
 		*t = GTX
+	case "GTD", "GOOD_TILL_DATE":
+		*t = GTD
 	default:
 		return errors.NewValidationError("time_in_force", fmt.Sprintf("unknown time in force: %s", string(text)))
 	}
 	return nil
 }
 
+// WorkingType is the price a trigger order's StopPrice is compared against.
+type WorkingType int
+
+const (
+	WorkingTypeContract WorkingType = iota // Last traded price (default)
+	WorkingTypeMark                        // Mark price
+	WorkingTypeIndex                       // Index price
+)
+
+// String implements fmt.Stringer.
+func (w WorkingType) String() string {
+	switch w {
+	case WorkingTypeContract:
+		return "CONTRACT_PRICE"
+	case WorkingTypeMark:
+		return "MARK_PRICE"
+	case WorkingTypeIndex:
+		return "INDEX_PRICE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (w WorkingType) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (w *WorkingType) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "CONTRACT_PRICE", "LAST_PRICE", "LASTPRICE":
+		*w = WorkingTypeContract
+	case "MARK_PRICE", "MARKPRICE":
+		*w = WorkingTypeMark
+	case "INDEX_PRICE", "INDEXPRICE":
+		*w = WorkingTypeIndex
+	default:
+		return errors.NewValidationError("working_type", fmt.Sprintf("unknown working type: %s", string(text)))
+	}
+	return nil
+}
+
 // Order represents a normalized order.
 type Order struct {
-	ID           string           `json:"id"`
-	ClientID     string           `json:"client_id,omitempty"`
-	Symbol       market.Symbol    `json:"symbol"`
-	Side         market.Side      `json:"side"`
-	Type         Type             `json:"type"`
-	Status       Status           `json:"status"`
-	Price        udecimal.Decimal `json:"price"`
-	Quantity     udecimal.Decimal `json:"quantity"`
-	ExecutedQty  udecimal.Decimal `json:"executed_qty"`
-	AvgPrice     udecimal.Decimal `json:"avg_price"`
-	StopPrice    udecimal.Decimal `json:"stop_price,omitempty"`
-	TimeInForce  TimeInForce      `json:"time_in_force"`
-	ReduceOnly   bool             `json:"reduce_only"`
-	CreatedAt    time.Time        `json:"created_at"`
-	UpdatedAt    time.Time        `json:"updated_at"`
+	ID              string           `json:"id"`
+	ClientID        string           `json:"client_id,omitempty"`
+	ReplacesOrderID string           `json:"replaces_order_id,omitempty"` // Order replaced via CancelReplace
+	Symbol          market.Symbol    `json:"symbol"`
+	Side            market.Side      `json:"side"`
+	Type            Type             `json:"type"`
+	Status          Status           `json:"status"`
+	Price           udecimal.Decimal `json:"price"`
+	Quantity        udecimal.Decimal `json:"quantity"`
+	ExecutedQty     udecimal.Decimal `json:"executed_qty"`
+	AvgPrice        udecimal.Decimal `json:"avg_price"`
+	StopPrice       udecimal.Decimal `json:"stop_price,omitempty"`
+	WorkingType     WorkingType      `json:"working_type"`          // Price StopPrice triggers on
+	IcebergQty      udecimal.Decimal `json:"iceberg_qty,omitempty"` // Visible quantity; zero if not an iceberg
+	TimeInForce     TimeInForce      `json:"time_in_force"`
+	GoodTillDate    time.Time        `json:"good_till_date,omitzero"` // Expiry of a GTD order
+	ReduceOnly      bool             `json:"reduce_only"`
+	Fills           []Fill           `json:"fills,omitempty"` // Executions, if the exchange reported them
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
 }
 
 // RemainingQty returns the remaining quantity to be filled.
@@ -257,6 +310,12 @@ func (o Order) FillPercent() (udecimal.Decimal, error) {
 	return o.ExecutedQty.Div(o.Quantity)
 }
 
+// FillPercentOr is like FillPercent but returns fallback if the quantity is
+// zero.
+func (o Order) FillPercentOr(fallback udecimal.Decimal) udecimal.Decimal {
+	return market.SafeDiv(o.ExecutedQty, o.Quantity, fallback)
+}
+
 // IsFilledPercent returns true if at least pct% of the order is filled.
 func (o Order) IsFilledPercent(pct udecimal.Decimal) (bool, error) {
 	fillPct, err := o.FillPercent()
@@ -266,20 +325,64 @@ func (o Order) IsFilledPercent(pct udecimal.Decimal) (bool, error) {
 	return fillPct.GreaterThanOrEqual(pct), nil
 }
 
+// Notional returns the order's value in the quote asset.
+// Once the order has executions it is AvgPrice * ExecutedQty; before that it is
+// Price * Quantity, which is zero for market orders that carry no price.
+func (o Order) Notional() udecimal.Decimal {
+	if o.ExecutedQty.IsPos() && !o.AvgPrice.IsZero() {
+		return o.ExecutedNotional()
+	}
+	return o.Price.Mul(o.Quantity)
+}
+
+// ExecutedNotional returns the executed value in the quote asset (AvgPrice * ExecutedQty).
+func (o Order) ExecutedNotional() udecimal.Decimal {
+	return o.AvgPrice.Mul(o.ExecutedQty)
+}
+
+// Age returns how long ago the order was created, or zero if CreatedAt is unset.
+func (o Order) Age(now time.Time) time.Duration {
+	if o.CreatedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(o.CreatedAt)
+}
+
+// TimeSinceUpdate returns how long ago the order last changed, or zero if
+// UpdatedAt is unset.
+func (o Order) TimeSinceUpdate(now time.Time) time.Duration {
+	if o.UpdatedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(o.UpdatedAt)
+}
+
+// IsStale returns true if the order is still open and older than maxAge,
+// e.g. a resting quote due to be cancelled. Orders without CreatedAt are
+// never stale.
+func (o Order) IsStale(maxAge time.Duration, now time.Time) bool {
+	return o.IsOpen() && !o.CreatedAt.IsZero() && o.Age(now) > maxAge
+}
+
 // Request represents a request to place a new order.
 type Request struct {
-	Symbol      market.Symbol    `json:"symbol"`
-	Side        market.Side      `json:"side"`
-	Type        Type             `json:"type"`
-	Quantity    udecimal.Decimal `json:"quantity"`
-	Price       udecimal.Decimal `json:"price,omitempty"`
-	StopPrice   udecimal.Decimal `json:"stop_price,omitempty"`
-	TimeInForce TimeInForce      `json:"time_in_force,omitempty"`
-	ClientID    string           `json:"client_id,omitempty"`
-	ReduceOnly  bool             `json:"reduce_only,omitempty"`
-}
-
-// Validate validates the order request.
+	Symbol       market.Symbol    `json:"symbol"`
+	Side         market.Side      `json:"side"`
+	Type         Type             `json:"type"`
+	Quantity     udecimal.Decimal `json:"quantity"`
+	Price        udecimal.Decimal `json:"price,omitempty"`
+	StopPrice    udecimal.Decimal `json:"stop_price,omitempty"`    // Activation price for a trailing stop, optional
+	CallbackRate udecimal.Decimal `json:"callback_rate,omitempty"` // Trailing stops only; distance from the extreme in percent
+	WorkingType  WorkingType      `json:"working_type,omitempty"`  // Trigger orders only; defaults to the last price
+	IcebergQty   udecimal.Decimal `json:"iceberg_qty,omitempty"`   // Visible quantity; zero if not an iceberg
+	TimeInForce  TimeInForce      `json:"time_in_force,omitempty"`
+	GoodTillDate time.Time        `json:"good_till_date,omitzero"` // Required for GTD, rejected otherwise
+	ClientID     string           `json:"client_id,omitempty"`
+	ReduceOnly   bool             `json:"reduce_only,omitempty"`
+}
+
+// Validate validates the order request. It does not compare GoodTillDate
+// with the current time; see ValidateAt.
 func (r *Request) Validate() error {
 	if !r.Symbol.IsValid() {
 		return errors.NewValidationError("symbol", "symbol is required")
@@ -293,12 +396,97 @@ func (r *Request) Validate() error {
 	if r.Type.IsLimit() && r.Price.IsZero() {
 		return errors.NewValidationError("price", "price is required for limit orders")
 	}
-	if r.Type.IsTrigger() && r.StopPrice.IsZero() {
+	if r.Type.IsTrigger() && r.Type != TypeTrailingStop && r.StopPrice.IsZero() {
 		return errors.NewValidationError("stop_price", "stop price is required for trigger orders")
 	}
+	if r.Type == TypeTrailingStop {
+		if !r.CallbackRate.IsPos() {
+			return errors.NewValidationError("callback_rate", "callback rate is required and must be positive for trailing stops")
+		}
+	} else if !r.CallbackRate.IsZero() {
+		return errors.NewValidationError("callback_rate", "callback rate is only valid for trailing stops")
+	}
+	if r.WorkingType != WorkingTypeContract && !r.Type.IsTrigger() {
+		return errors.NewValidationError("working_type", "working type is only valid for trigger orders")
+	}
+	if !r.IcebergQty.IsZero() {
+		if r.IcebergQty.IsNeg() || r.IcebergQty.GreaterThanOrEqual(r.Quantity) {
+			return errors.NewValidationError("iceberg_qty", "must be positive and less than quantity")
+		}
+		if r.Type != TypeLimit || r.TimeInForce != GTC {
+			return errors.NewValidationError("iceberg_qty", "iceberg orders must be GTC limit orders")
+		}
+	}
+	if r.ClientID != "" {
+		if err := validateClientID(r.ClientID); err != nil {
+			return err
+		}
+	}
+	if r.TimeInForce == GTD {
+		if r.GoodTillDate.IsZero() {
+			return errors.NewValidationError("good_till_date", "good till date is required for GTD")
+		}
+	} else if !r.GoodTillDate.IsZero() {
+		return errors.NewValidationError("good_till_date", "good till date requires GTD time in force")
+	}
+	return nil
+}
+
+// ValidateAt validates the request as Validate does and also checks that a
+// GTD order's GoodTillDate is after now. Clients pass the time of their
+// configured clock, so orders in replayed or simulated time validate against
+// that time rather than the wall clock.
+func (r *Request) ValidateAt(now time.Time) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.TimeInForce == GTD && !r.GoodTillDate.After(now) {
+		return errors.NewValidationError("good_till_date", "good till date must be in the future")
+	}
+	return nil
+}
+
+// Position is the view of an open position used by ValidateAgainst.
+// account.Position satisfies it.
+type Position interface {
+	IsLong() bool
+	IsShort() bool
+	AbsQty() udecimal.Decimal
+}
+
+// ValidateAgainst validates the request and, if it is reduce-only, checks it
+// against the current position: a reduce-only buy needs an open short, a
+// reduce-only sell an open long, and the quantity may not exceed the position
+// size. This rejects orders that would open or flip a position.
+// Pass a nil Position when there is no open position.
+func (r *Request) ValidateAgainst(pos Position) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if !r.ReduceOnly {
+		return nil
+	}
+	if pos == nil || pos.AbsQty().IsZero() {
+		return errors.NewValidationError("reduce_only", "no open position to reduce")
+	}
+	if r.Side == market.SideBuy && !pos.IsShort() {
+		return errors.NewValidationError("reduce_only", "reduce-only buy requires a short position")
+	}
+	if r.Side == market.SideSell && !pos.IsLong() {
+		return errors.NewValidationError("reduce_only", "reduce-only sell requires a long position")
+	}
+	if r.Quantity.GreaterThan(pos.AbsQty()) {
+		return errors.NewValidationError("quantity", fmt.Sprintf("reduce-only quantity %s exceeds position size %s", r.Quantity, pos.AbsQty()))
+	}
 	return nil
 }
 
+// Notional returns the requested value in the quote asset (Price * Quantity).
+// It is zero for market orders, which carry no price.
+func (r *Request) Notional() udecimal.Decimal {
+	return r.Price.Mul(r.Quantity)
+}
+
 // CancelRequest represents a request to cancel an order.
 type CancelRequest struct {
 	Symbol   market.Symbol `json:"symbol"`
@@ -328,3 +516,69 @@ type Balance struct {
 func (b Balance) Total() udecimal.Decimal {
 	return b.Free.Add(b.Locked)
 }
+
+// BalanceChangeReason is why a balance changed, as reported by the exchange.
+type BalanceChangeReason int
+
+const (
+	BalanceChangeUnknown  BalanceChangeReason = iota // Not reported by the exchange
+	BalanceChangeTrade                               // A fill, including its commission
+	BalanceChangeOrder                               // Funds locked or released by an order
+	BalanceChangeFunding                             // A funding fee
+	BalanceChangeTransfer                            // A deposit, withdrawal or transfer between accounts
+)
+
+// String implements fmt.Stringer.
+func (r BalanceChangeReason) String() string {
+	switch r {
+	case BalanceChangeUnknown:
+		return "UNKNOWN"
+	case BalanceChangeTrade:
+		return "TRADE"
+	case BalanceChangeOrder:
+		return "ORDER"
+	case BalanceChangeFunding:
+		return "FUNDING"
+	case BalanceChangeTransfer:
+		return "TRANSFER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r BalanceChangeReason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *BalanceChangeReason) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "UNKNOWN":
+		*r = BalanceChangeUnknown
+	case "TRADE":
+		*r = BalanceChangeTrade
+	case "ORDER":
+		*r = BalanceChangeOrder
+	case "FUNDING", "FUNDING_FEE":
+		*r = BalanceChangeFunding
+	case "TRANSFER", "DEPOSIT", "WITHDRAW":
+		*r = BalanceChangeTransfer
+	default:
+		return errors.NewValidationError("reason", fmt.Sprintf("unknown balance change reason: %s", string(text)))
+	}
+	return nil
+}
+
+// BalanceUpdate is the new balance of one asset after a change.
+type BalanceUpdate struct {
+	Balance
+	Delta     udecimal.Decimal    `json:"delta"` // Change in Total; zero if funds only moved between free and locked
+	Reason    BalanceChangeReason `json:"reason"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// ExchangeTime returns the exchange event time (see stream.ExchangeTimer).
+func (u BalanceUpdate) ExchangeTime() time.Time {
+	return u.Timestamp
+}
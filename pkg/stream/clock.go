@@ -0,0 +1,184 @@
+package stream
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time and the timers measured against it.
+// Substitute a simulated clock (see ManualClock) to drive streams and clients
+// from a backtest or a test: timestamps, heartbeats, staleness checks,
+// reconnect delays and drain timeouts all follow it.
+type Clock interface {
+	Now() time.Time
+
+	// After returns a channel that receives the clock's time once d has
+	// elapsed on it.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTicker returns a ticker that fires every d on the clock. d must be
+	// positive.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks of a Clock. Like time.Ticker, it drops ticks for a
+// slow receiver.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements Clock.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTicker implements Clock.
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// ManualClock is a Clock that only moves when advanced, for tests and
+// backtests stepping through simulated time. It is safe for concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*manualTimer
+	added   chan struct{} // Closed when a timer is added; nil until awaited
+}
+
+// manualTimer is a pending After or ticker of a ManualClock.
+type manualTimer struct {
+	due    time.Time
+	period time.Duration // Zero for After
+	ch     chan time.Time
+}
+
+// NewManualClock returns a ManualClock set to start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now implements Clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements Clock. A d <= 0 fires immediately.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{due: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- c.now
+		return t.ch
+	}
+	c.add(t)
+	return t.ch
+}
+
+// NewTicker implements Clock. It panics if d is not positive, as
+// time.NewTicker does.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("stream: non-positive interval for ManualClock.NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{due: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.add(t)
+	return manualTicker{clock: c, t: t}
+}
+
+// add registers t and wakes BlockUntil. c.mu must be held.
+func (c *ManualClock) add(t *manualTimer) {
+	c.waiters = append(c.waiters, t)
+	if c.added != nil {
+		close(c.added)
+		c.added = nil
+	}
+}
+
+// BlockUntil blocks until at least n timers and tickers are pending on c, so
+// a test can advance the clock knowing the code under test waits on it. It
+// returns ctx.Err() if ctx is done first.
+func (c *ManualClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			c.mu.Unlock()
+			return nil
+		}
+		if c.added == nil {
+			c.added = make(chan struct{})
+		}
+		added := c.added
+		c.mu.Unlock()
+
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Advance moves the clock forward by d and fires every timer that falls due.
+// A ticker fires at most once per Advance.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waiters = slices.DeleteFunc(c.waiters, func(t *manualTimer) bool {
+		if t.due.After(c.now) {
+			return false
+		}
+		select {
+		case t.ch <- c.now:
+		default: // Receiver behind; drop the tick
+		}
+		if t.period == 0 {
+			return true
+		}
+		for !t.due.After(c.now) {
+			t.due = t.due.Add(t.period)
+		}
+		return false
+	})
+}
+
+// stop removes t from the pending timers.
+func (c *ManualClock) stop(t *manualTimer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waiters = slices.DeleteFunc(c.waiters, func(w *manualTimer) bool { return w == t })
+}
+
+type manualTicker struct {
+	clock *ManualClock
+	t     *manualTimer
+}
+
+func (t manualTicker) C() <-chan time.Time { return t.t.ch }
+func (t manualTicker) Stop()               { t.clock.stop(t.t) }
@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(start)
+	after := c.After(time.Minute)
+	ticker := c.NewTicker(20 * time.Second)
+	defer ticker.Stop()
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("After fired early")
+	default:
+	}
+	if got := <-ticker.C(); !got.Equal(start.Add(30 * time.Second)) {
+		t.Fatalf("tick at %v, want %v", got, start.Add(30*time.Second))
+	}
+
+	c.Advance(30 * time.Second)
+	if got := <-after; !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("After fired at %v, want %v", got, start.Add(time.Minute))
+	}
+	<-ticker.C()
+
+	ticker.Stop()
+	c.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+}
+
+// advanceUntil advances c by step every few milliseconds of real time until
+// done is closed, since the stream registers its timers asynchronously.
+func advanceUntil(t *testing.T, c *ManualClock, step time.Duration, done <-chan struct{}) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-done:
+			return
+		case <-deadline:
+			t.Fatal("timed out advancing the clock")
+		case <-time.After(5 * time.Millisecond):
+			c.Advance(step)
+		}
+	}
+}
+
+// TestHeartbeatManualClock checks that heartbeats follow Config.Clock rather
+// than real time.
+func TestHeartbeatManualClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := DefaultConfig()
+	cfg.HeartbeatInterval = time.Hour
+	cfg.Clock = clock
+	s := NewBaseStream[int](cfg)
+	if err := s.Start(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = s.Stop() }()
+
+	select {
+	case <-s.Heartbeats():
+		t.Fatal("heartbeat without the clock advancing")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	got := make(chan struct{})
+	go func() {
+		<-s.Heartbeats()
+		close(got)
+	}()
+	advanceUntil(t, clock, time.Hour, got)
+}
+
+// TestRunWithReconnectManualClock checks that the reconnect delay is measured
+// on Config.Clock.
+func TestRunWithReconnectManualClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := DefaultConfig()
+	cfg.ReconnectBaseDelay = time.Hour
+	cfg.ReconnectMaxDelay = time.Hour
+	cfg.Clock = clock
+	s := NewBaseStream[int](cfg)
+
+	sessions := make(chan struct{}, 2)
+	go func() {
+		_ = s.RunWithReconnect(ctx, func(ctx context.Context) error {
+			sessions <- struct{}{}
+			return stderrors.New("connection lost")
+		})
+	}()
+	<-sessions
+
+	select {
+	case <-sessions:
+		t.Fatal("reconnected without the clock advancing")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	reconnected := make(chan struct{})
+	go func() {
+		<-sessions
+		close(reconnected)
+	}()
+	advanceUntil(t, clock, time.Hour, reconnected)
+}
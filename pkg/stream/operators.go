@@ -0,0 +1,218 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// operator is a Stream derived from a source stream. Subscribing subscribes
+// the source, and source errors are forwarded to the operator's error channel.
+type operator[In, Out any] struct {
+	*BaseStream[Out]
+	src Stream[In]
+	run func(ctx context.Context, in <-chan In, out chan<- Out)
+}
+
+// newOperator creates an operator whose data channel is buffered per cfg.
+// A zero BufferSize makes the data channel unbuffered, so values are only
+// handed over when the consumer reads. The operator takes the labels of src
+// if it has any.
+func newOperator[In, Out any](src Stream[In], cfg Config, run func(ctx context.Context, in <-chan In, out chan<- Out)) *operator[In, Out] {
+	base := NewBaseStream[Out](cfg)
+	base.dataCh = make(chan Out, cfg.BufferSize)
+	if labeled, ok := src.(interface{ Labels() Labels }); ok {
+		base.SetLabels(labeled.Labels())
+	}
+	return &operator[In, Out]{BaseStream: base, src: src, run: run}
+}
+
+// Subscribe subscribes the source and starts the operator.
+func (o *operator[In, Out]) Subscribe(ctx context.Context) (<-chan Out, error) {
+	in, err := o.src.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := o.DataChannel()
+	err = o.Start(ctx, func(ctx context.Context) error {
+		var wg sync.WaitGroup
+		wg.Go(func() { o.forwardErrors(ctx) })
+		o.run(ctx, in, out)
+		// The source is exhausted or we were cancelled; either way we are done.
+		_ = o.Stop()
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		_ = o.src.Unsubscribe(ctx)
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe stops the operator and unsubscribes the source.
+func (o *operator[In, Out]) Unsubscribe(ctx context.Context) error {
+	if err := o.Stop(); err != nil {
+		return err
+	}
+	return o.src.Unsubscribe(ctx)
+}
+
+// forwardErrors relays source errors until the source error channel closes
+// or ctx is cancelled.
+func (o *operator[In, Out]) forwardErrors(ctx context.Context) {
+	errs := o.src.Errors()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			o.EmitError(err)
+		}
+	}
+}
+
+// Coalesce returns a stream that, while the consumer is not reading, folds
+// incoming values into a single pending value using merge, and delivers it on
+// the consumer's next read. Unlike latest-wins, no update is lost: the pending
+// value accumulates every update since the last read.
+//
+// merge returns false when next cannot be folded into pending (e.g. a sequence
+// gap); pending is then delivered first, blocking until the consumer reads it,
+// and next becomes the new pending value.
+func Coalesce[T any](src Stream[T], merge func(pending, next T) (T, bool)) Stream[T] {
+	cfg := DefaultConfig()
+	cfg.BufferSize = 0
+	return newOperator(src, cfg, func(ctx context.Context, in <-chan T, out chan<- T) {
+		var (
+			pending    T
+			hasPending bool
+		)
+		for {
+			// A nil channel disables the send case until something is pending.
+			var send chan<- T
+			if hasPending {
+				send = out
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case send <- pending:
+				hasPending = false
+			case v, ok := <-in:
+				if !ok {
+					if hasPending {
+						select {
+						case out <- pending:
+						case <-ctx.Done():
+						}
+					}
+					return
+				}
+				if !hasPending {
+					pending, hasPending = v, true
+					continue
+				}
+				merged, ok := merge(pending, v)
+				if ok {
+					pending = merged
+					continue
+				}
+				select {
+				case out <- pending:
+					pending = v
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	})
+}
+
+// Transform returns a stream that calls fn for every value of src. fn may
+// emit any number of values, including none, and may keep state between
+// calls since it always runs on the same goroutine. Output is buffered as
+// in DefaultConfig; emit blocks only once the buffer is full, so a consumer
+// that falls that far behind applies backpressure to src.
+func Transform[In, Out any](src Stream[In], fn func(v In, emit func(Out))) Stream[Out] {
+	return newOperator(src, DefaultConfig(), func(ctx context.Context, in <-chan In, out chan<- Out) {
+		emit := func(v Out) {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				fn(v, emit)
+			}
+		}
+	})
+}
+
+// Filter returns a stream of the values of src for which keep returns true.
+func Filter[T any](src Stream[T], keep func(T) bool) Stream[T] {
+	return Transform(src, func(v T, emit func(T)) {
+		if keep(v) {
+			emit(v)
+		}
+	})
+}
+
+// Throttle returns a stream that forwards at most one value of src per
+// interval, always the most recent: the first value passes immediately, and
+// values arriving within the next interval replace each other until it ends.
+// A consumer slower than interval sees the newest value on its next read.
+// When src ends, a value still held back is delivered before the stream
+// closes. Errors are forwarded as they occur.
+func Throttle[T any](src Stream[T], interval time.Duration) Stream[T] {
+	cfg := DefaultConfig()
+	cfg.BufferSize = 0
+	return newOperator(src, cfg, func(ctx context.Context, in <-chan T, out chan<- T) {
+		var (
+			pending    T
+			hasPending bool
+			wait       <-chan time.Time // Non-nil while the interval after a send runs
+		)
+		timer := time.NewTimer(interval)
+		timer.Stop()
+		defer timer.Stop()
+		for {
+			// A nil channel disables the send case until a value may go out.
+			var send chan<- T
+			if hasPending && wait == nil {
+				send = out
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case send <- pending:
+				hasPending = false
+				timer.Reset(interval)
+				wait = timer.C
+			case <-wait:
+				wait = nil
+			case v, ok := <-in:
+				if !ok {
+					if hasPending {
+						select {
+						case out <- pending:
+						case <-ctx.Done():
+						}
+					}
+					return
+				}
+				pending, hasPending = v, true
+			}
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"math"
+	"time"
+)
+
+// ReconnectStrategy decides how long a stream waits before reconnecting.
+type ReconnectStrategy interface {
+	// NextDelay returns the delay before the given reconnect attempt (1-based).
+	NextDelay(attempt int) time.Duration
+}
+
+// ExponentialBackoff doubles the delay with every attempt, starting at Base.
+// Delays are capped at Max; a zero Max leaves them uncapped.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements ReconnectStrategy.
+func (b ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	delay := b.Base
+	for i := 1; i < attempt && delay <= math.MaxInt64/2; i++ {
+		if b.Max > 0 && delay >= b.Max {
+			break
+		}
+		delay *= 2
+	}
+	if b.Max > 0 {
+		return min(delay, b.Max)
+	}
+	return delay
+}
+
+// ConstantBackoff waits Delay before every attempt.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// NextDelay implements ReconnectStrategy.
+func (b ConstantBackoff) NextDelay(int) time.Duration {
+	return b.Delay
+}
+
+// LinearBackoff waits Base before the first attempt and Step longer before
+// each one after. Delays are capped at Max; a zero Max leaves them uncapped.
+type LinearBackoff struct {
+	Base time.Duration
+	Step time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements ReconnectStrategy.
+func (b LinearBackoff) NextDelay(attempt int) time.Duration {
+	n := time.Duration(max(attempt-1, 0))
+	delay := b.Base
+	if b.Step > 0 && n > (math.MaxInt64-b.Base)/b.Step {
+		delay = math.MaxInt64
+	} else {
+		delay += n * b.Step
+	}
+	if b.Max > 0 {
+		return min(delay, b.Max)
+	}
+	return delay
+}
@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// replayPollInterval bounds how long a replay sleeps before rereading its
+// clock, so a simulated clock that is advanced by hand is noticed promptly.
+const replayPollInterval = 10 * time.Millisecond
+
+// replayStream emits recorded events as its clock reaches their timestamps.
+type replayStream[T any] struct {
+	*BaseStream[T]
+	events []Timestamped[T]
+	speed  float64
+}
+
+// NewReplayStream returns a stream that replays recorded events through the
+// Stream interface, so a strategy runs the same code over history as live.
+//
+// Each event is due at its RecvAt, or its ExchangeAt if RecvAt is zero. Replay
+// starts at the first event when the stream is subscribed; from then on the
+// time elapsed on clock, multiplied by speed, is added to the replay time and
+// every event due is emitted in timestamp order. A speed of 0 or less emits
+// the events back to back without waiting. Combine it with
+// exchange.WithClock to drive a client from the same clock.
+//
+// Nothing is dropped: the data channel is unbuffered and the replay waits for
+// the consumer, which pauses it. The stream stops after the last event.
+func NewReplayStream[T any](events []Timestamped[T], clock Clock, speed float64) Stream[T] {
+	cfg := DefaultConfig()
+	cfg.Clock = clock
+	base := NewBaseStream[T](cfg)
+	base.dataCh = make(chan T)
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b Timestamped[T]) int {
+		return replayTime(a).Compare(replayTime(b))
+	})
+	return &replayStream[T]{BaseStream: base, events: sorted, speed: speed}
+}
+
+// Subscribe starts the replay.
+func (s *replayStream[T]) Subscribe(ctx context.Context) (<-chan T, error) {
+	out := s.DataChannel()
+	if err := s.Start(ctx, s.run); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unsubscribe stops the replay.
+func (s *replayStream[T]) Unsubscribe(ctx context.Context) error {
+	return s.Stop()
+}
+
+// run emits the events as they fall due and stops the stream after the last.
+func (s *replayStream[T]) run(ctx context.Context) error {
+	defer func() { _ = s.Stop() }()
+	if len(s.events) == 0 {
+		return nil
+	}
+	s.MarkConnected()
+	out := s.DataChannel()
+	clock := s.Config().clock()
+	start, origin := clock.Now(), replayTime(s.events[0])
+	for _, ev := range s.events {
+		if s.speed > 0 {
+			if !s.wait(ctx, clock, start, origin, replayTime(ev)) {
+				return nil
+			}
+		}
+		select {
+		case out <- ev.Value:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// wait blocks until the replay time, origin plus the scaled time elapsed on
+// clock since start, reaches due. It returns false if ctx is cancelled first.
+func (s *replayStream[T]) wait(ctx context.Context, clock Clock, start, origin, due time.Time) bool {
+	for {
+		elapsed := time.Duration(float64(clock.Now().Sub(start)) * s.speed)
+		remaining := due.Sub(origin.Add(elapsed))
+		if remaining <= 0 {
+			return true
+		}
+		delay := min(time.Duration(float64(remaining)/s.speed), replayPollInterval)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// replayTime returns the time an event is due.
+func replayTime[T any](ev Timestamped[T]) time.Time {
+	if ev.RecvAt.IsZero() {
+		return ev.ExchangeAt
+	}
+	return ev.RecvAt
+}
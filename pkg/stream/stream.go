@@ -5,10 +5,13 @@ package stream
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/pwnholic/clara/internal/infra"
 	"github.com/pwnholic/clara/pkg/errors"
 )
 
@@ -36,6 +39,25 @@ type Stream[T any] interface {
 
 	// State returns the current stream state.
 	State() State
+
+	// StreamInfo returns the stream's connection health, including reconnect
+	// progress and the last error.
+	StreamInfo() StreamInfo
+
+	// Heartbeats returns a channel that receives the current time whenever the
+	// stream is active but has emitted nothing for Config.HeartbeatInterval.
+	// It lets consumers tell a quiet feed from a dead one. The channel never
+	// fires if HeartbeatInterval is zero and is closed when the stream stops.
+	Heartbeats() <-chan time.Time
+}
+
+// StreamInfo is a point-in-time view of a stream's connection health.
+type StreamInfo struct {
+	State             State
+	ReconnectAttempt  int       // Current reconnect attempt; 0 while connected
+	RemainingAttempts int       // Reconnect attempts left; -1 if unlimited
+	LastError         error     // Error that caused the latest disconnect
+	ConnectedSince    time.Time // Zero while not connected
 }
 
 // State represents the current state of a stream.
@@ -70,6 +92,32 @@ func (s State) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *State) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "idle":
+		*s = StateIdle
+	case "connecting":
+		*s = StateConnecting
+	case "active":
+		*s = StateActive
+	case "reconnecting":
+		*s = StateReconnecting
+	case "closing":
+		*s = StateClosing
+	case "closed":
+		*s = StateClosed
+	default:
+		return errors.NewValidationError("state", fmt.Sprintf("unknown stream state: %s", string(text)))
+	}
+	return nil
+}
+
 // Config holds common configuration for streams.
 type Config struct {
 	// BufferSize is the channel buffer size for emitted data.
@@ -88,11 +136,45 @@ type Config struct {
 	// ReconnectMaxDelay is the maximum delay between reconnection attempts.
 	ReconnectMaxDelay time.Duration
 
+	// ReconnectStrategy computes the delay before each reconnection attempt.
+	// If nil, ExponentialBackoff from ReconnectBaseDelay to ReconnectMaxDelay is used.
+	ReconnectStrategy ReconnectStrategy
+
 	// PingInterval is the interval for sending keepalive pings.
 	PingInterval time.Duration
 
 	// PongTimeout is the timeout for receiving pong responses.
 	PongTimeout time.Duration
+
+	// DrainOnClose keeps the data channel open after Unsubscribe until the
+	// consumer has read every buffered value or DrainTimeout elapses.
+	// New values are not accepted while draining.
+	DrainOnClose bool
+
+	// DrainTimeout bounds how long a closing stream waits for the consumer
+	// to drain buffered values. Must be positive when DrainOnClose is set.
+	DrainTimeout time.Duration
+
+	// HeartbeatInterval is how long an active stream may go without emitting
+	// before a heartbeat is sent on the Heartbeats channel (0 = disabled).
+	HeartbeatInterval time.Duration
+
+	// ReplayLast caches the most recently delivered value and delivers it on
+	// the data channel as soon as the stream starts, so a new subscriber
+	// does not wait for the next update. The replayed value may be stale.
+	// Values Emit drops on a full channel are not cached. Start never blocks
+	// on the replay: without buffer room it is sent before run is called.
+	ReplayLast bool
+
+	// EnableCompression negotiates permessage-deflate on WebSocket dials and
+	// inflates compressed frames transparently, roughly halving the bandwidth
+	// of deep order book streams. Servers that do not support it are used
+	// uncompressed.
+	EnableCompression bool
+
+	// Clock stamps emits and connection times and drives the heartbeat,
+	// reconnect delays and DrainTimeout (nil = SystemClock).
+	Clock Clock
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -105,7 +187,49 @@ func DefaultConfig() Config {
 		ReconnectMaxDelay:    30 * time.Second,
 		PingInterval:         30 * time.Second,
 		PongTimeout:          10 * time.Second,
+		DrainTimeout:         5 * time.Second,
+	}
+}
+
+// Option overrides part of a Config for a single stream, e.g. a deep buffer
+// for a bursty trade stream on a client whose default suits tickers.
+type Option func(*Config)
+
+// WithBufferSize sets the data channel buffer size of the stream.
+func WithBufferSize(n int) Option {
+	return func(c *Config) {
+		c.BufferSize = n
+	}
+}
+
+// WithReplayLast sets Config.ReplayLast on the stream.
+func WithReplayLast() Option {
+	return func(c *Config) {
+		c.ReplayLast = true
+	}
+}
+
+// WithCompression sets Config.EnableCompression on the stream.
+func WithCompression() Option {
+	return func(c *Config) {
+		c.EnableCompression = true
+	}
+}
+
+// With returns a copy of c with opts applied.
+func (c Config) With(opts ...Option) Config {
+	for _, opt := range opts {
+		opt(&c)
 	}
+	return c
+}
+
+// clock returns Clock, defaulting to SystemClock.
+func (c Config) clock() Clock {
+	if c.Clock == nil {
+		return SystemClock
+	}
+	return c.Clock
 }
 
 // Validate validates the configuration.
@@ -122,19 +246,44 @@ func (c Config) Validate() error {
 	if c.ReconnectMaxDelay < c.ReconnectBaseDelay {
 		return errors.NewValidationError("reconnect_max_delay", "must be >= reconnect_base_delay")
 	}
+	if c.DrainTimeout < 0 || (c.DrainOnClose && c.DrainTimeout == 0) {
+		return errors.NewValidationError("drain_timeout", "must be positive when drain_on_close is set")
+	}
+	if c.HeartbeatInterval < 0 {
+		return errors.NewValidationError("heartbeat_interval", "must be non-negative")
+	}
 	return nil
 }
 
+// Labels identify a stream in the errors it emits.
+type Labels struct {
+	Provider string // Exchange provider name
+	Stream   string // Stream name (e.g., "ticker", "orderbook")
+	Symbol   string // Trading symbol, empty for account-wide streams
+}
+
+// droppedErrorLogInterval is the minimum time between two logs of errors
+// dropped by EmitError.
+const droppedErrorLogInterval = 10 * time.Second
+
 // BaseStream provides common functionality for stream implementations.
 // Embed this in your stream implementations to get basic state management.
 type BaseStream[T any] struct {
 	mu       sync.RWMutex
 	state    atomic.Int32
 	config   Config
+	labels   Labels
+	info     StreamInfo
+	lastEmit atomic.Int64      // Unix nanoseconds of the last Emit
+	last     atomic.Pointer[T] // Most recent value, cached if ReplayLast is set
 	dataCh   chan T
 	errorCh  chan error
+	hbCh     chan time.Time
 	doneCh   chan struct{}
 	cancel   context.CancelFunc
+
+	dropped     atomic.Int64 // Errors dropped since the last drop was logged
+	lastDropLog atomic.Int64 // Unix nanoseconds of the last drop log
 }
 
 // NewBaseStream creates a new BaseStream with the given configuration.
@@ -143,6 +292,7 @@ func NewBaseStream[T any](cfg Config) *BaseStream[T] {
 		config:  cfg,
 		doneCh:  make(chan struct{}),
 		errorCh: make(chan error, 10),
+		hbCh:    make(chan time.Time, 1),
 	}
 }
 
@@ -190,60 +340,234 @@ func (s *BaseStream[T]) Errors() <-chan error {
 	return s.errorCh
 }
 
+// Heartbeats returns the heartbeat channel.
+func (s *BaseStream[T]) Heartbeats() <-chan time.Time {
+	return s.hbCh
+}
+
 // Emit sends data to the data channel. Non-blocking.
-// Returns true if sent, false if channel full or closed.
+// Returns true if sent, false if channel full or the stream is closing.
 func (s *BaseStream[T]) Emit(data T) bool {
+	if state := s.State(); state == StateClosing || state == StateClosed {
+		return false
+	}
 	select {
 	case s.DataChannel() <- data:
+		if s.config.ReplayLast {
+			s.last.Store(&data)
+		}
+		if s.config.HeartbeatInterval > 0 {
+			s.lastEmit.Store(s.Now().UnixNano())
+		}
 		return true
 	default:
 		return false
 	}
 }
 
-// EmitError sends an error to the error channel. Non-blocking.
+// Now returns the current time from Config.Clock.
+func (s *BaseStream[T]) Now() time.Time {
+	return s.config.clock().Now()
+}
+
+// Last returns the cached most recent value. It is only populated when
+// Config.ReplayLast is set.
+func (s *BaseStream[T]) Last() (T, bool) {
+	if v := s.last.Load(); v != nil {
+		return *v, true
+	}
+	var zero T
+	return zero, false
+}
+
+// SetLast seeds the value replayed on Start, for streams whose last value is
+// known before they emit (e.g., a subscriber joining a shared upstream).
+// It has no effect unless Config.ReplayLast is set.
+func (s *BaseStream[T]) SetLast(v T) {
+	if s.config.ReplayLast {
+		s.last.Store(&v)
+	}
+}
+
+// SetLabels sets the labels attached to emitted errors.
+// Must be called before Start.
+func (s *BaseStream[T]) SetLabels(labels Labels) {
+	s.labels = labels
+}
+
+// Labels returns the stream labels.
+func (s *BaseStream[T]) Labels() Labels {
+	return s.labels
+}
+
+// EmitError sends an error to the error channel. Non-blocking: if the
+// channel is full the error is dropped, and drops are logged with their count
+// at most once per droppedErrorLogInterval.
+// If labels are set, the error is wrapped in an errors.StreamError carrying
+// them; an error that already is a StreamError keeps its own labels and only
+// has missing ones filled in.
 func (s *BaseStream[T]) EmitError(err error) {
+	err = s.labelError(err)
 	select {
 	case s.errorCh <- err:
 	default:
-		// Error channel full, drop the error
+		// Error channel full: drop the error, logging the drops at most once
+		// per droppedErrorLogInterval so an error storm does not flood the log.
+		s.dropped.Add(1)
+		now, last := s.Now().UnixNano(), s.lastDropLog.Load()
+		if now-last < int64(droppedErrorLogInterval) || !s.lastDropLog.CompareAndSwap(last, now) {
+			return
+		}
+		infra.WithErr(infra.Warn(), err).Int64("dropped", s.dropped.Swap(0)).
+			Msg("stream errors dropped: error channel full")
+	}
+}
+
+// labelError attributes err to this stream.
+func (s *BaseStream[T]) labelError(err error) error {
+	if s.labels == (Labels{}) {
+		return err
+	}
+	if streamErr, ok := err.(*errors.StreamError); ok {
+		labeled := *streamErr
+		if labeled.Provider == "" {
+			labeled.Provider = s.labels.Provider
+		}
+		if labeled.Stream == "" {
+			labeled.Stream = s.labels.Stream
+		}
+		if labeled.Symbol == "" {
+			labeled.Symbol = s.labels.Symbol
+		}
+		return &labeled
+	}
+	if _, ok := errors.AsStreamError(err); ok {
+		return err
+	}
+	return &errors.StreamError{
+		Provider: s.labels.Provider,
+		Stream:   s.labels.Stream,
+		Symbol:   s.labels.Symbol,
+		Err:      err,
 	}
 }
 
 // Start begins the stream with the given run function.
-// The run function should block until context is cancelled.
+// The run function should block until context is cancelled. If it returns an
+// error before that, the error is emitted and the stream is stopped.
 func (s *BaseStream[T]) Start(ctx context.Context, run func(ctx context.Context) error) error {
 	if !s.compareAndSwapState(StateIdle, StateConnecting) {
 		return errors.ErrCancelled // Already running
 	}
 
 	ctx, s.cancel = context.WithCancel(ctx)
+	ch := s.DataChannel()
+	var replay *T
+	if len(ch) == 0 { // Values emitted before Start are already buffered and newer
+		if v := s.last.Load(); v != nil {
+			select {
+			case ch <- *v:
+			default:
+				replay = v // No buffer room; the run goroutine delivers it
+			}
+		}
+	}
+	runDone := make(chan struct{})
+	hbDone := make(chan struct{})
 
-	// Close channels when done
+	// Close channels once cancelled and run has returned, so run never
+	// sends on a closed channel.
 	go func() {
 		<-ctx.Done()
+		<-runDone
+		<-hbDone
 		s.mu.Lock()
-		if s.dataCh != nil {
-			close(s.dataCh)
-			s.dataCh = nil
-		}
+		dataCh := s.dataCh
+		s.dataCh = nil
 		s.mu.Unlock()
+		if dataCh != nil {
+			s.drain(dataCh)
+			close(dataCh)
+		}
 		close(s.errorCh)
+		close(s.hbCh)
 		close(s.doneCh)
 		s.setState(StateClosed)
 	}()
 
 	// Run the stream
 	go func() {
+		defer close(runDone)
 		s.setState(StateActive)
+		if replay != nil {
+			select {
+			case ch <- *replay:
+			case <-ctx.Done():
+				return
+			}
+		}
 		if err := run(ctx); err != nil && ctx.Err() == nil {
 			s.EmitError(err)
+			_ = s.Stop()
 		}
 	}()
 
+	go s.heartbeat(ctx, hbDone)
+
 	return nil
 }
 
+// heartbeat sends on the heartbeat channel whenever the stream is active and
+// nothing was emitted during the last HeartbeatInterval. It closes done on return.
+func (s *BaseStream[T]) heartbeat(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	interval := s.config.HeartbeatInterval
+	if interval <= 0 {
+		return
+	}
+	s.lastEmit.Store(s.Now().UnixNano())
+	ticker := s.config.clock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			now := s.Now()
+			if s.State() != StateActive || now.Sub(time.Unix(0, s.lastEmit.Load())) < interval {
+				continue
+			}
+			select {
+			case s.hbCh <- now:
+			default:
+				// Previous heartbeat not yet read
+			}
+		}
+	}
+}
+
+// drainPollInterval is how often a draining stream checks for an empty buffer.
+// It is real time: polling must go on while a simulated clock stands still.
+const drainPollInterval = 10 * time.Millisecond
+
+// drain waits, if DrainOnClose is set, until the consumer has read every
+// buffered value in ch or DrainTimeout elapses.
+func (s *BaseStream[T]) drain(ch chan T) {
+	if !s.config.DrainOnClose || len(ch) == 0 {
+		return
+	}
+	timeout := s.config.clock().After(s.config.DrainTimeout)
+	poll := time.NewTicker(drainPollInterval)
+	defer poll.Stop()
+	for len(ch) > 0 {
+		select {
+		case <-timeout:
+			return
+		case <-poll.C:
+		}
+	}
+}
+
 // Stop stops the stream.
 func (s *BaseStream[T]) Stop() error {
 	if s.State() == StateClosed || s.State() == StateIdle {
@@ -257,6 +581,80 @@ func (s *BaseStream[T]) Stop() error {
 	return nil
 }
 
+// StreamInfo returns the stream's connection health.
+func (s *BaseStream[T]) StreamInfo() StreamInfo {
+	s.mu.RLock()
+	info := s.info
+	s.mu.RUnlock()
+	info.State = s.State()
+	info.RemainingAttempts = -1
+	if limit := s.config.MaxReconnectAttempts; limit > 0 {
+		info.RemainingAttempts = max(limit-info.ReconnectAttempt, 0)
+	}
+	return info
+}
+
+// MarkConnected records that the underlying connection is established.
+// Session functions passed to RunWithReconnect call it once connected.
+func (s *BaseStream[T]) MarkConnected() {
+	s.mu.Lock()
+	s.info.ReconnectAttempt = 0
+	s.info.ConnectedSince = s.Now()
+	s.mu.Unlock()
+	s.compareAndSwapState(StateConnecting, StateActive)
+	s.compareAndSwapState(StateReconnecting, StateActive)
+}
+
+// RunWithReconnect runs session until ctx is cancelled, reconnecting after the
+// Config.ReconnectStrategy delay whenever it returns. session should call
+// MarkConnected once connected and block until the connection is lost.
+//
+// Each disconnect is recorded in StreamInfo and emitted on the error channel.
+// Returns the last session error if Reconnect is disabled or
+// MaxReconnectAttempts is exhausted (which stops the stream when used as the
+// Start run function), and nil once ctx is cancelled.
+func (s *BaseStream[T]) RunWithReconnect(ctx context.Context, session func(ctx context.Context) error) error {
+	for {
+		err := session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		s.mu.Lock()
+		s.info.LastError = err
+		s.info.ConnectedSince = time.Time{}
+		s.info.ReconnectAttempt++
+		attempt := s.info.ReconnectAttempt
+		s.mu.Unlock()
+
+		if !s.config.Reconnect {
+			return err
+		}
+		if limit := s.config.MaxReconnectAttempts; limit > 0 && attempt > limit {
+			return fmt.Errorf("giving up after %d reconnect attempts: %w", limit, err)
+		}
+		if err != nil {
+			s.EmitError(err)
+		}
+		s.setState(StateReconnecting)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.config.clock().After(s.reconnectDelay(attempt)):
+		}
+	}
+}
+
+// reconnectDelay returns the delay before attempt (1-based) from the configured strategy.
+func (s *BaseStream[T]) reconnectDelay(attempt int) time.Duration {
+	strategy := s.config.ReconnectStrategy
+	if strategy == nil {
+		strategy = ExponentialBackoff{Base: s.config.ReconnectBaseDelay, Max: s.config.ReconnectMaxDelay}
+	}
+	return max(strategy.NextDelay(attempt), 0)
+}
+
 // Config returns the stream configuration.
 func (s *BaseStream[T]) Config() Config {
 	return s.config
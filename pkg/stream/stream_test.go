@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestStartReplayUnbuffered checks that replaying the last value on a data
+// channel without buffer room does not block Start, and that the value is
+// still delivered before anything run emits.
+func TestStartReplayUnbuffered(t *testing.T) {
+	cfg := DefaultConfig().With(WithBufferSize(0), WithReplayLast())
+	s := NewBaseStream[int](cfg)
+	s.dataCh = make(chan int)
+	s.SetLast(1)
+
+	started := make(chan error, 1)
+	go func() {
+		started <- s.Start(context.Background(), func(ctx context.Context) error {
+			s.dataCh <- 2
+			<-ctx.Done()
+			return nil
+		})
+	}()
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start blocked on the replayed value")
+	}
+	defer func() { _ = s.Stop() }()
+
+	for _, want := range []int{1, 2} {
+		select {
+		case got := <-s.dataCh:
+			if got != want {
+				t.Fatalf("got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("value %d not delivered", want)
+		}
+	}
+}
+
+// TestEmitDroppedNotReplayed checks that a value Emit drops on a full channel
+// does not become the replayed value.
+func TestEmitDroppedNotReplayed(t *testing.T) {
+	s := NewBaseStream[int](DefaultConfig().With(WithReplayLast()))
+	s.dataCh = make(chan int, 1)
+	if !s.Emit(1) {
+		t.Fatal("Emit(1) dropped with buffer room")
+	}
+	if s.Emit(2) {
+		t.Fatal("Emit(2) sent on a full channel")
+	}
+	if v, ok := s.Last(); !ok || v != 1 {
+		t.Fatalf("Last = %d, %t; want 1, true", v, ok)
+	}
+}
@@ -0,0 +1,27 @@
+package stream
+
+import (
+	"context"
+	"time"
+)
+
+// SubscribeFor subscribes s for a bounded window. Once d elapses or ctx is
+// cancelled, s is unsubscribed and the returned channel is closed, so no
+// timer or Unsubscribe call is needed to avoid leaking the stream.
+func SubscribeFor[T any](ctx context.Context, s Stream[T], d time.Duration) (<-chan T, error) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	ch, err := s.Subscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			_ = s.Unsubscribe(context.WithoutCancel(ctx))
+		case <-s.Done():
+		}
+	}()
+	return ch, nil
+}
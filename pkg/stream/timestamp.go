@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"context"
+	"time"
+)
+
+// Timestamped pairs a stream value with the local time it was received and,
+// when the value carries one, the exchange's event time.
+type Timestamped[T any] struct {
+	Value      T
+	RecvAt     time.Time // Local receive time
+	ExchangeAt time.Time // Exchange event time; zero if unknown
+}
+
+// Latency returns RecvAt - ExchangeAt, or zero if the exchange time is unknown.
+func (t Timestamped[T]) Latency() time.Duration {
+	if t.ExchangeAt.IsZero() {
+		return 0
+	}
+	return t.RecvAt.Sub(t.ExchangeAt)
+}
+
+// ExchangeTimer is implemented by values that carry the exchange's event time,
+// such as market.Ticker, market.Trade and market.OrderBook.
+type ExchangeTimer interface {
+	ExchangeTime() time.Time
+}
+
+// WithTimestamps returns a stream that stamps each value of src with the time
+// it was received, and with its ExchangeTime if the value implements
+// ExchangeTimer, for measuring exchange-to-app latency. Receive times come
+// from src's Config.Clock when src exposes its Config (as BaseStream does).
+func WithTimestamps[T any](src Stream[T]) Stream[Timestamped[T]] {
+	cfg := DefaultConfig()
+	if c, ok := src.(interface{ Config() Config }); ok {
+		cfg.Clock = c.Config().Clock
+	}
+	clock := cfg.clock()
+	return newOperator(src, cfg, func(ctx context.Context, in <-chan T, out chan<- Timestamped[T]) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				ts := Timestamped[T]{Value: v, RecvAt: clock.Now()}
+				if et, ok := any(v).(ExchangeTimer); ok {
+					ts.ExchangeAt = et.ExchangeTime()
+				}
+				select {
+				case out <- ts:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	})
+}